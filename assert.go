@@ -33,10 +33,140 @@ type Assert struct {
 	XMLDecoderFunc  func(io.Reader) *xml.Decoder
 	Golden          *golden.Golden
 
+	// JSONLinesEncoderFunc returns the encoder used to write each line of a
+	// JSON Lines stream. It must not be configured to indent output, as each
+	// encoded value must fit on a single line. Lines are decoded with
+	// JSONDecoderFunc.
+	JSONLinesEncoderFunc func(io.Writer) *json.Encoder
+
+	// JSONSchemas holds JSON Schemas per type, which JSON marshaled output and
+	// JSON golden files of values of each type must conform to. Pointer types
+	// are dereferenced when looking up a schema. Use SetJSONSchema to register
+	// a schema.
+	JSONSchemas map[reflect.Type]JSONSchemaValidator
+
+	// JSONSchema is a JSON Schema which JSON marshaled output and JSON golden
+	// files of all values must conform to. It takes precedence over
+	// JSONSchemas. Use WithJSONSchema to set a schema for a single call.
+	JSONSchema JSONSchemaValidator
+
+	// StrictXMLDecoding enables an additional check when unmarshaling XML
+	// golden files, which fails if the golden file contains any elements or
+	// attributes that are not consumed by the target type. This matches the
+	// unknown field strictness of the default JSON and YAML decoders.
+	//
+	// Types implementing xml.Unmarshaler are assumed to consume all of their
+	// content.
+	StrictXMLDecoding bool
+
+	// CanonicalXML enables Exclusive XML Canonicalization (C14N) of marshaled
+	// XML, so golden files hold the canonical byte representation of a value,
+	// and golden files are canonicalized before being compared. Useful for
+	// types that are signed, like SAML and XML-DSig documents.
+	//
+	// Whitespace between elements is retained by canonicalization, so the
+	// XMLEncoderFunc should be set to an encoder which does not indent when
+	// golden files are to match the representation sent on the wire.
+	CanonicalXML bool
+
+	// XMLHeader enables prepending the standard XML declaration
+	// (<?xml version="1.0" encoding="UTF-8"?>) to marshaled XML, and thereby
+	// expecting it to be present in golden files.
+	XMLHeader bool
+
+	// XMLRootName sets the root element name used when marshaling non-struct
+	// values to XML, instead of the Go type name (<int>, <bool>, etc.).
+	XMLRootName string
+
+	// XMLTrailingNewline enables appending a trailing newline to marshaled
+	// XML, matching the trailing newline of JSON and YAML golden files.
+	XMLTrailingNewline bool
+
+	// XMLNamespace sets the default namespace declared on the root element
+	// when marshaling XML. It is also used as the default namespace for
+	// unadorned elements when unmarshaling XML golden files. Values of unnamed
+	// types, like []int or anonymous structs, are marshaled without it unless
+	// XMLRootName is set.
+	XMLNamespace string
+
+	// FormKeyFunc returns the form key of a nested struct field, map entry or
+	// slice element given the key of its parent. Defaults to DottedFormKey,
+	// producing keys like "address.city". Set it to BracketFormKey for keys
+	// like "address[city]".
+	FormKeyFunc func(parent, child string) string
+
+	// ConsistencyKeyFunc maps the keys of JSON objects, YAML mappings, and XML
+	// elements and attributes to a common form before CrossFormatConsistency
+	// compares them. Defaults to NormalizedKey, which ignores case,
+	// underscores and hyphens. Set it to ExactKey to require identical keys
+	// across formats.
+	ConsistencyKeyFunc func(format Format, key string) string
+
+	// CheckIdempotency enables a third stage in the JSON, YAML and XML
+	// marshaling assertions, which marshals the value unmarshaled from the
+	// golden file, and verifies that the result matches the golden file. This
+	// catches custom marshaling and unmarshaling code which is lossy or
+	// asymmetric, as the unmarshaled value is otherwise only compared with the
+	// expected value.
+	CheckIdempotency bool
+
+	// DumpUnexported enables rendering of unexported struct fields by Dump.
+	DumpUnexported bool
+
+	// UpdateFilterFunc returns a filter selecting golden files to update, in
+	// addition to all golden files being updated when the Golden field is in
+	// update mode. The filter is a comma-separated list of formats, like "json"
+	// or "yaml,xml", and regular expressions matched against test names, like
+	// "TestBook.*/full". Entries of only lowercase letters and underscores are
+	// formats, which are golden file names without the "goldsert_" prefix,
+	// like "toml" or "msgpack_json". A golden file is updated if its format is
+	// listed, or no formats are listed, and its test name matches any of the
+	// expressions, or no expressions are listed. All other golden files are
+	// asserted as usual. Values like "1" and "true" select all golden files,
+	// and an empty value selects none.
+	//
+	// Defaults to EnvUpdateFilterFunc, which returns the value of the
+	// GOLDSERT_UPDATE environment variable.
+	UpdateFilterFunc func() string
+
+	// CreateMissingFunc reports if create missing mode is enabled, in which
+	// golden files which do not exist are created with the marshaled output,
+	// logging a notice, rather than failing the test. Existing golden files
+	// are never modified, and are asserted as usual. Like update mode, create
+	// missing mode is refused in CI environments, as reported by CIFunc.
+	//
+	// Defaults to EnvCreateMissingFunc, which checks the
+	// GOLDSERT_CREATE_MISSING environment variable.
+	CreateMissingFunc func() bool
+
+	// CIFunc reports if tests run in a CI environment, in which case tests fail
+	// when golden files are to be updated, rather than passing without
+	// checking anything. Set the GOLDSERT_ALLOW_CI_UPDATE environment variable
+	// to "1" or "true" to allow updates in CI anyway, or set CIFunc to nil to
+	// disable detection.
+	//
+	// Defaults to EnvCIFunc, which checks the CI environment variable.
+	CIFunc func() bool
+
+	// ReviewFunc reports if review mode is enabled, in which golden files are
+	// not read directly. Instead, when marshaled output does not match a golden
+	// file, or the golden file is missing, the output is written to a pending
+	// golden file next to it with a ".new" suffix, and the test fails. Pending
+	// golden files can be accepted or rejected with the "goldsert review"
+	// command. Update mode takes precedence over review mode.
+	//
+	// Defaults to EnvReviewFunc, which checks the GOLDSERT_REVIEW environment
+	// variable.
+	ReviewFunc func() bool
+
 	// NormalizeLineBreaks enables line-break normalization which replaces
 	// Windows' CRLF (\r\n) and Mac Classic CR (\r) line breaks with Unix's LF
 	// (\n) line breaks.
 	NormalizeLineBreaks bool
+
+	// logf logs notices, like golden files being created, instead of the
+	// Logf method of the test when set.
+	logf func(t *testing.T, format string, args ...interface{})
 }
 
 // New returns a new *Assert instance configured with default settings.
@@ -48,14 +178,21 @@ type Assert struct {
 // present on the provided struct.
 func New() *Assert {
 	return &Assert{
-		JSONEncoderFunc:     newJSONEncoder,
-		JSONDecoderFunc:     newJSONDecoder,
-		YAMLEncoderFunc:     newYAMLEncoder,
-		YAMLDecoderFunc:     newYAMLDecoder,
-		XMLEncoderFunc:      newXMLEncoder,
-		XMLDecoderFunc:      newXMLDecoder,
-		Golden:              golden.New(),
-		NormalizeLineBreaks: true,
+		JSONEncoderFunc:      newJSONEncoder,
+		JSONDecoderFunc:      newJSONDecoder,
+		YAMLEncoderFunc:      newYAMLEncoder,
+		YAMLDecoderFunc:      newYAMLDecoder,
+		XMLEncoderFunc:       newXMLEncoder,
+		XMLDecoderFunc:       newXMLDecoder,
+		Golden:               golden.New(),
+		JSONLinesEncoderFunc: newJSONLinesEncoder,
+		FormKeyFunc:          DottedFormKey,
+		ConsistencyKeyFunc:   NormalizedKey,
+		UpdateFilterFunc:     EnvUpdateFilterFunc,
+		CreateMissingFunc:    EnvCreateMissingFunc,
+		CIFunc:               EnvCIFunc,
+		ReviewFunc:           EnvReviewFunc,
+		NormalizeLineBreaks:  true,
 	}
 }
 
@@ -82,27 +219,12 @@ func (s *Assert) JSONMarshalingP(t *testing.T, v, want interface{}) {
 	err := s.JSONEncoderFunc(&buf).Encode(v)
 	require.NoErrorf(t, err, "failed to JSON marshal %T: %+v", v, v)
 
-	marshaled := buf.Bytes()
-	if s.NormalizeLineBreaks {
-		marshaled = normalizeLineBreaks(marshaled)
-	}
-
-	if s.Golden.Update() {
-		s.Golden.SetP(t, "goldsert_json", marshaled)
-	}
-
-	gold := s.Golden.GetP(t, "goldsert_json")
-	if s.NormalizeLineBreaks {
-		gold = normalizeLineBreaks(gold)
-	}
+	marshaled := s.normalize(buf.Bytes())
+	gold := s.GoldenP(t, "goldsert_json", marshaled, jsonEqual)
 	assert.JSONEq(t, string(gold), string(marshaled))
+	s.validateJSONSchema(t, v, marshaled, gold)
 
-	if reflect.ValueOf(want).Kind() != reflect.Ptr {
-		require.FailNowf(t,
-			"only pointer types can be asserted",
-			"%T is not a pointer type", want,
-		)
-	}
+	requirePtr(t, want)
 
 	got := reflect.New(reflect.TypeOf(want).Elem()).Interface()
 	err = s.JSONDecoderFunc(bytes.NewBuffer(gold)).Decode(got)
@@ -113,6 +235,10 @@ func (s *Assert) JSONMarshalingP(t *testing.T, v, want interface{}) {
 	assert.Equal(t, want, got,
 		"unmarshaling from golden file does not match expected object",
 	)
+
+	if s.CheckIdempotency {
+		s.assertIdempotent(t, JSON, got, gold)
+	}
 }
 
 // YAMLMarshaling asserts that the given "v" value YAML marshals to an expected
@@ -138,27 +264,11 @@ func (s *Assert) YAMLMarshalingP(t *testing.T, v, want interface{}) {
 	err := s.YAMLEncoderFunc(&buf).Encode(v)
 	require.NoErrorf(t, err, "failed to YAML marshal %T: %+v", v, v)
 
-	marshaled := buf.Bytes()
-	if s.NormalizeLineBreaks {
-		marshaled = normalizeLineBreaks(marshaled)
-	}
-
-	if s.Golden.Update() {
-		s.Golden.SetP(t, "goldsert_yaml", marshaled)
-	}
-
-	gold := s.Golden.GetP(t, "goldsert_yaml")
-	if s.NormalizeLineBreaks {
-		gold = normalizeLineBreaks(gold)
-	}
+	marshaled := s.normalize(buf.Bytes())
+	gold := s.GoldenP(t, "goldsert_yaml", marshaled, yamlEqual)
 	assert.YAMLEq(t, string(gold), string(marshaled))
 
-	if reflect.ValueOf(want).Kind() != reflect.Ptr {
-		require.FailNowf(t,
-			"only pointer types can be asserted",
-			"%T is not a pointer type", want,
-		)
-	}
+	requirePtr(t, want)
 
 	got := reflect.New(reflect.TypeOf(want).Elem()).Interface()
 	err = s.YAMLDecoderFunc(bytes.NewBuffer(gold)).Decode(got)
@@ -169,6 +279,10 @@ func (s *Assert) YAMLMarshalingP(t *testing.T, v, want interface{}) {
 	assert.Equal(t, want, got,
 		"unmarshaling from golden file does not match expected object",
 	)
+
+	if s.CheckIdempotency {
+		s.assertIdempotent(t, YAML, got, gold)
+	}
 }
 
 // XMLMarshaling asserts that the given "v" value XML marshals to an expected
@@ -190,41 +304,48 @@ func (s *Assert) XMLMarshaling(t *testing.T, v interface{}) {
 func (s *Assert) XMLMarshalingP(t *testing.T, v, want interface{}) {
 	t.Helper()
 
-	var buf bytes.Buffer
-	err := s.XMLEncoderFunc(&buf).Encode(v)
+	marshaled, err := s.marshalXML(v)
 	require.NoErrorf(t, err, "failed to XML marshal %T: %+v", v, v)
 
-	marshaled := buf.Bytes()
-	if s.NormalizeLineBreaks {
-		marshaled = normalizeLineBreaks(marshaled)
-	}
-
-	if s.Golden.Update() {
-		s.Golden.SetP(t, "goldsert_xml", marshaled)
+	marshaled = s.normalize(marshaled)
+	if s.CanonicalXML {
+		marshaled, err = canonicalizeXML(marshaled)
+		require.NoErrorf(t, err, "failed to canonicalize XML of %T: %+v", v, v)
 	}
 
-	gold := s.Golden.GetP(t, "goldsert_xml")
-	if s.NormalizeLineBreaks {
-		gold = normalizeLineBreaks(gold)
-	}
-	assert.Equal(t, string(gold), string(marshaled))
-
-	if reflect.ValueOf(want).Kind() != reflect.Ptr {
-		require.FailNowf(t,
-			"only pointer types can be asserted",
-			"%T is not a pointer type", want,
+	gold := s.GoldenP(t, "goldsert_xml", marshaled, s.xmlEqual)
+	goldXML := gold
+	if s.CanonicalXML {
+		goldXML, err = canonicalizeXML(gold)
+		require.NoErrorf(t, err,
+			"failed to canonicalize XML from %s",
+			s.Golden.FileP(t, "goldsert_xml"),
 		)
 	}
+	assert.Equal(t, string(goldXML), string(marshaled))
+
+	requirePtr(t, want)
 
 	got := reflect.New(reflect.TypeOf(want).Elem()).Interface()
-	err = s.XMLDecoderFunc(bytes.NewBuffer(gold)).Decode(got)
+	err = s.newXMLDecoder(bytes.NewBuffer(gold)).Decode(got)
 	require.NoErrorf(t, err,
 		"failed to XML unmarshal %T from %s",
 		got, s.Golden.FileP(t, "goldsert_xml"),
 	)
+	if s.StrictXMLDecoding {
+		err = checkXMLKnownFields(gold, reflect.TypeOf(got))
+		require.NoErrorf(t, err,
+			"failed to strictly XML unmarshal %T from %s",
+			got, s.Golden.FileP(t, "goldsert_xml"),
+		)
+	}
 	assert.Equal(t, want, got,
 		"unmarshaling from golden file does not match expected object",
 	)
+
+	if s.CheckIdempotency {
+		s.assertIdempotent(t, XML, got, goldXML)
+	}
 }
 
 // newJSONEncoder is the default JSONEncoderFunc used by Assert. It returns a
@@ -245,6 +366,12 @@ func newJSONDecoder(r io.Reader) *json.Decoder {
 	return dec
 }
 
+// newJSONLinesEncoder is the default JSONLinesEncoderFunc used by Assert. It
+// returns a *json.Encoder which writes compact single-line output.
+func newJSONLinesEncoder(w io.Writer) *json.Encoder {
+	return json.NewEncoder(w)
+}
+
 // newYAMLEncoder is the default YAMLEncoderFunc used by Assert. It returns a
 // *yaml.Encoder which is set to indent with two spaces.
 func newYAMLEncoder(w io.Writer) *yaml.Encoder {
@@ -277,6 +404,109 @@ func newXMLDecoder(r io.Reader) *xml.Decoder {
 	return xml.NewDecoder(r)
 }
 
+// GoldenP writes data to the named golden file of the test when it is to be
+// updated, and returns the content of the golden file, with line breaks
+// normalized if NormalizeLineBreaks is enabled. It lets assertions of
+// additional formats handle golden files like the assertion methods of Assert
+// do, including refusing to update golden files in CI environments, creating
+// missing golden files in create missing mode, and writing pending golden
+// files in review mode.
+//
+// The equal function reports if data equals the golden file, in which case
+// the golden file is not written, and defaults to bytes.Equal if it is nil.
+func (s *Assert) GoldenP(
+	t *testing.T,
+	name string,
+	data []byte,
+	equal func(gold, data []byte) bool,
+) []byte {
+	t.Helper()
+
+	return s.goldenP(t, name, data, equal, s.normalize)
+}
+
+// GoldenBinaryP is like GoldenP, but never normalizes line breaks, for golden
+// files holding binary data.
+func (s *Assert) GoldenBinaryP(
+	t *testing.T,
+	name string,
+	data []byte,
+	equal func(gold, data []byte) bool,
+) []byte {
+	t.Helper()
+
+	return s.goldenP(t, name, data, equal, keepLineBreaks)
+}
+
+// goldenP implements GoldenP and GoldenBinaryP, normalizing golden files with
+// the given normalize function.
+func (s *Assert) goldenP(
+	t *testing.T,
+	name string,
+	data []byte,
+	equal func(gold, data []byte) bool,
+	normalize func([]byte) []byte,
+) []byte {
+	t.Helper()
+
+	if equal == nil {
+		equal = bytes.Equal
+	}
+
+	s.touch(t, name)
+	if s.update(t, name) {
+		s.write(t, name, data, equal, normalize)
+	} else {
+		s.createMissing(t, name, data)
+		if s.ReviewFunc != nil && s.ReviewFunc() {
+			return s.review(t, name, data, equal, normalize)
+		}
+	}
+
+	return normalize(s.Golden.GetP(t, name))
+}
+
+// normalize returns data with line breaks normalized if NormalizeLineBreaks is
+// enabled.
+func (s *Assert) normalize(data []byte) []byte {
+	if s.NormalizeLineBreaks {
+		return normalizeLineBreaks(data)
+	}
+
+	return data
+}
+
+// keepLineBreaks returns data as is, for golden files which are never
+// normalized.
+func keepLineBreaks(data []byte) []byte {
+	return data
+}
+
+// log logs a notice for the test through logf, or the Logf method of the test
+// if logf is not set.
+func (s *Assert) log(t *testing.T, format string, args ...interface{}) {
+	t.Helper()
+
+	if s.logf != nil {
+		s.logf(t, format, args...)
+
+		return
+	}
+
+	t.Logf(format, args...)
+}
+
+func requirePtr(t *testing.T, v interface{}) {
+	t.Helper()
+
+	if reflect.ValueOf(v).Kind() != reflect.Ptr {
+		require.FailNowf(t,
+			"only pointer types can be asserted",
+			"%T is not a pointer type", v,
+		)
+	}
+}
+
 func normalizeLineBreaks(data []byte) []byte {
 	// Replace CRLF (\r\n, windows) with LF (\n, unix)
 	result := bytes.ReplaceAll(data, []byte{13, 10}, []byte{10})
@@ -1,7 +1,13 @@
 package goldsert
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAssert_JSONMarshaling(t *testing.T) {
@@ -63,3 +69,100 @@ func TestAssert_XMLMarshalingP(t *testing.T) {
 		})
 	}
 }
+
+func TestAssert_GoldenP(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goldsert-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	setenv(t, map[string]string{"GOLDSERT_ALLOW_CI_UPDATE": ""})
+
+	gs := New()
+	gs.Golden.Dirname = dir
+	gs.Golden.UpdateFunc = func() bool { return true }
+	gs.UpdateFilterFunc = nil
+	gs.CIFunc = func() bool { return false }
+	file := gs.Golden.FileP(t, "goldsert_ini")
+
+	got := gs.GoldenP(t, "goldsert_ini", []byte("a = 1\r\n"), nil)
+
+	assert.Equal(t, "a = 1\n", string(got))
+	got = gs.GoldenBinaryP(t, "goldsert_bin", []byte("\x01\r\n"), nil)
+	assert.Equal(t, "\x01\r\n", string(got), "binary data must be kept as is")
+
+	gs.CIFunc = func() bool { return true }
+	ok, output := runTest(t, func(t *testing.T) {
+		gs.GoldenP(t, "goldsert_ini", []byte("a = 2\n"), nil)
+	})
+
+	assert.False(t, ok, "update in CI must fail the test")
+	assert.Contains(t, output, "refusing to update golden files in CI")
+	b, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, "a = 1\r\n", string(b), "golden file must not be modified")
+}
+
+func TestAssert_GoldenP_Review(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goldsert-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	gs := New()
+	gs.Golden.Dirname = dir
+	gs.Golden.UpdateFunc = func() bool { return false }
+	gs.UpdateFilterFunc = nil
+	gs.CreateMissingFunc = nil
+	gs.ReviewFunc = func() bool { return true }
+	logs := captureLogs(gs)
+	file := gs.Golden.FileP(t, "goldsert_ini")
+	pending := file + pendingSuffix
+	require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+	require.NoError(t, ioutil.WriteFile(file, []byte("a = 1\r\n"), 0o644))
+
+	got := gs.GoldenP(t, "goldsert_ini", []byte("a = 1\n"), nil)
+
+	assert.Equal(t, "a = 1\n", string(got))
+	assert.NoFileExists(t, pending)
+
+	got = gs.GoldenP(t, "goldsert_ini", []byte("a = 2\n"), nil)
+
+	assert.Equal(t, "a = 1\n", string(got))
+	b, err := ioutil.ReadFile(pending)
+	require.NoError(t, err)
+	assert.Equal(t, "a = 2\n", string(b))
+	b, err = ioutil.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, "a = 1\r\n", string(b), "golden file must not be modified")
+	assert.Equal(t,
+		"goldsert: wrote pending golden file for review: "+pending+"\n",
+		logs.String(),
+	)
+}
+
+func TestAssert_GoldenP_CreateMissing(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goldsert-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	gs := New()
+	gs.Golden.Dirname = dir
+	gs.Golden.UpdateFunc = func() bool { return false }
+	gs.UpdateFilterFunc = nil
+	gs.CreateMissingFunc = func() bool { return true }
+	gs.ReviewFunc = nil
+	gs.CIFunc = func() bool { return false }
+	logs := captureLogs(gs)
+	file := gs.Golden.FileP(t, "goldsert_ini")
+
+	got := gs.GoldenP(t, "goldsert_ini", []byte("a = 1\n"), nil)
+
+	assert.Equal(t, "a = 1\n", string(got))
+	assert.Equal(t,
+		"goldsert: created missing golden file: "+file+"\n", logs.String(),
+	)
+
+	got = gs.GoldenP(t, "goldsert_ini", []byte("a = 2\n"), nil)
+
+	assert.Equal(t, "a = 1\n", string(got),
+		"existing golden file must not be modified",
+	)
+}
@@ -0,0 +1,183 @@
+package bson
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/jimeh/go-goldsert"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+)
+
+// Assert holds both configuration and implementation of all BSON assertion
+// methods.
+//
+// You can customize serialization by setting the EncoderFunc and DecoderFunc
+// fields to a custom function that returns an encoder/decoder configured as
+// you need, for example with a custom registry.
+//
+// Golden files are handled by the Goldsert field, whose Golden field configures
+// golden file generation, and whose NormalizeLineBreaks field enables
+// line-break normalization of Extended JSON golden files. See the
+// github.com/jimeh/go-goldsert package for details about what can be
+// configured.
+type Assert struct {
+	EncoderFunc func(bsonrw.ValueWriter) (*bson.Encoder, error)
+	DecoderFunc func(bsonrw.ValueReader) (*bson.Decoder, error)
+	Goldsert    *goldsert.Assert
+
+	// Canonical enables canonical mode Extended JSON for golden files, which
+	// preserves all BSON type information. When disabled, relaxed mode is used
+	// instead, which is easier to read but loses some type information, for
+	// example the distinction between int32 and int64 values.
+	Canonical bool
+}
+
+// New returns a new *Assert instance configured with default settings.
+//
+// The default encoder and decoder use the default BSON registry, and golden
+// files use canonical mode Extended JSON.
+func New() *Assert {
+	return &Assert{
+		EncoderFunc: bson.NewEncoder,
+		DecoderFunc: bson.NewDecoder,
+		Goldsert:    goldsert.New(),
+		Canonical:   true,
+	}
+}
+
+// BSONMarshaling asserts that the given "v" value BSON marshals to an expected
+// value fetched from a golden file on disk, and then verifies that the
+// marshaled result produces a value that is equal to "v" when unmarshaled.
+//
+// Used for objects that do NOT change when they are marshaled and unmarshaled.
+func (s *Assert) BSONMarshaling(t *testing.T, v interface{}) {
+	t.Helper()
+
+	s.BSONMarshalingP(t, v, v)
+}
+
+// BSONMarshalingP asserts that the given "v" value BSON marshals to an expected
+// value fetched from a golden file on disk, and then verifies that the
+// marshaled result produces a value that is equal to "want" when unmarshaled.
+//
+// Used for objects that change when they are marshaled and unmarshaled.
+func (s *Assert) BSONMarshalingP(t *testing.T, v, want interface{}) {
+	t.Helper()
+
+	marshaled, err := s.marshal(v)
+	require.NoErrorf(t, err, "failed to BSON marshal %T: %+v", v, v)
+
+	marshaledJSON, err := s.toExtJSON(marshaled)
+	require.NoErrorf(t, err,
+		"failed to convert BSON of %T to Extended JSON: %+v", v, v,
+	)
+
+	gold := s.Goldsert.GoldenP(t,
+		"goldsert_bson", marshaledJSON, s.extJSONEqual,
+	)
+
+	goldBSON, err := s.fromExtJSON(gold)
+	require.NoErrorf(t, err,
+		"failed to convert Extended JSON from %s to BSON",
+		s.Goldsert.Golden.FileP(t, "goldsert_bson"),
+	)
+	goldJSON, err := s.toExtJSON(goldBSON)
+	require.NoErrorf(t, err,
+		"failed to convert BSON from %s to Extended JSON",
+		s.Goldsert.Golden.FileP(t, "goldsert_bson"),
+	)
+
+	assert.Equal(t, string(goldJSON), string(marshaledJSON))
+
+	if reflect.ValueOf(want).Kind() != reflect.Ptr {
+		require.FailNowf(t,
+			"only pointer types can be asserted",
+			"%T is not a pointer type", want,
+		)
+	}
+
+	got := reflect.New(reflect.TypeOf(want).Elem()).Interface()
+	err = s.unmarshal(goldBSON, got)
+	require.NoErrorf(t, err,
+		"failed to BSON unmarshal %T from %s",
+		got, s.Goldsert.Golden.FileP(t, "goldsert_bson"),
+	)
+	assert.Equal(t, want, got,
+		"unmarshaling from golden file does not match expected object",
+	)
+}
+
+func (s *Assert) marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	vw, err := bsonrw.NewBSONValueWriter(&buf)
+	if err != nil {
+		return nil, err
+	}
+
+	enc, err := s.EncoderFunc(vw)
+	if err != nil {
+		return nil, err
+	}
+
+	err = enc.Encode(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (s *Assert) unmarshal(data []byte, v interface{}) error {
+	dec, err := s.DecoderFunc(bsonrw.NewBSONDocumentReader(data))
+	if err != nil {
+		return err
+	}
+
+	return dec.Decode(v)
+}
+
+// toExtJSON converts the given BSON document to indented Extended JSON with a
+// trailing newline.
+func (s *Assert) toExtJSON(data []byte) ([]byte, error) {
+	b, err := bson.MarshalExtJSON(bson.Raw(data), s.Canonical, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	err = bson.IndentExtJSON(&buf, b, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteString("\n")
+
+	return buf.Bytes(), nil
+}
+
+// fromExtJSON converts the given Extended JSON document to BSON.
+func (s *Assert) fromExtJSON(data []byte) ([]byte, error) {
+	vr, err := bsonrw.NewExtJSONValueReader(
+		bytes.NewReader(data), s.Canonical,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return bsonrw.Copier{}.CopyDocumentToBytes(vr)
+}
+
+// extJSONEqual returns true if the Extended JSON golden file converts to the
+// given Extended JSON data, when converted to BSON and back.
+func (s *Assert) extJSONEqual(gold, data []byte) bool {
+	b, err := s.fromExtJSON(gold)
+	if err != nil {
+		return false
+	}
+	b, err = s.toExtJSON(b)
+
+	return err == nil && bytes.Equal(b, data)
+}
@@ -0,0 +1,74 @@
+package bson
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssert_BSONMarshaling(t *testing.T) {
+	for _, tt := range marshalingTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+
+			gs.BSONMarshaling(t, tt.v)
+		})
+	}
+}
+
+func TestAssert_BSONMarshalingP(t *testing.T) {
+	for _, tt := range marshalingPTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+
+			gs.BSONMarshalingP(t, tt.v, tt.want)
+		})
+	}
+}
+
+func TestAssert_BSONMarshaling_Relaxed(t *testing.T) {
+	for _, tt := range marshalingTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+			gs.Canonical = false
+
+			gs.BSONMarshaling(t, tt.v)
+		})
+	}
+}
+
+func TestAssert_extJSONEqual(t *testing.T) {
+	data := "{\n  \"n\": {\n    \"$numberInt\": \"1\"\n  }\n}\n"
+	tests := []struct {
+		name string
+		gold string
+		want bool
+	}{
+		{name: "equal", gold: data, want: true},
+		{
+			name: "compact",
+			gold: `{"n":{"$numberInt":"1"}}`,
+			want: true,
+		},
+		{
+			name: "changed",
+			gold: `{"n":{"$numberInt":"2"}}`,
+			want: false,
+		},
+		{
+			name: "changed type",
+			gold: `{"n":{"$numberLong":"1"}}`,
+			want: false,
+		},
+		{name: "invalid", gold: `{"n":`, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+
+			got := gs.extJSONEqual([]byte(tt.gold), []byte(data))
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Package bson provides goldsert test helpers for BSON, which use golden files
+// to assert marshaling and unmarshaling of given objects.
+//
+// It lives in its own module so that the core goldsert module does not depend
+// on the MongoDB Go driver.
+//
+// As BSON is a binary format, golden files store marshaled documents as
+// indented MongoDB Extended JSON, in canonical mode by default. Canonical mode
+// preserves BSON types, so changing a field from an int32 to an int64 is caught
+// just like renaming a field via its bson struct tag.
+//
+// Each test helper operates in two stages:
+//
+// Firstly they marshal the provided object to BSON and reads the corresponding
+// golden file from disk, followed by converting the golden file back to BSON
+// and verifying both documents are identical, including field order and
+// types.
+//
+// Secondly they unmarshal the BSON document converted from the golden file,
+// and verifies that the result is identical to the original object.
+//
+// Usage
+//
+//  func TestAccountMarshaling(t *testing.T) {
+//      bson.BSONMarshaling(t, &Account{Email: "jane@example.com"})
+//  }
+//
+// The above example will read from the following golden file:
+//
+//  testdata/TestAccountMarshaling/goldsert_bson.golden
+//
+// Note that Go maps, including bson.M, are marshaled in random order, which
+// makes golden files unstable. Use structs or bson.D for ordered documents.
+//
+// To create/update golden files, simply set the GOLDEN_UPDATE environment
+// variable to one of "1", "y", "t", "yes", "on", or "true" when running tests.
+package bson
+
+import (
+	"testing"
+)
+
+var global = New()
+
+// BSONMarshaling asserts that the given "v" value BSON marshals to an expected
+// value fetched from a golden file on disk, and then verifies that the
+// marshaled result produces a value that is equal to "v" when unmarshaled.
+//
+// Used for objects that do NOT change when they are marshaled and unmarshaled.
+func BSONMarshaling(t *testing.T, v interface{}) {
+	t.Helper()
+
+	global.BSONMarshaling(t, v)
+}
+
+// BSONMarshalingP asserts that the given "v" value BSON marshals to an expected
+// value fetched from a golden file on disk, and then verifies that the
+// marshaled result produces a value that is equal to "want" when unmarshaled.
+//
+// Used for objects that change when they are marshaled and unmarshaled.
+func BSONMarshalingP(t *testing.T, v, want interface{}) {
+	t.Helper()
+
+	global.BSONMarshalingP(t, v, want)
+}
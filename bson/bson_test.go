@@ -0,0 +1,155 @@
+package bson
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+//
+// Helpers
+//
+
+type Address struct {
+	Street string `bson:"street"`
+	City   string `bson:"city"`
+}
+
+type Timestamps struct {
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at,omitempty"`
+}
+
+type Account struct {
+	ID       primitive.ObjectID `bson:"_id"`
+	Email    string             `bson:"email"`
+	Logins   int64              `bson:"logins"`
+	Credits  int32              `bson:"credits,omitempty"`
+	Balance  float64            `bson:"balance,omitempty"`
+	Roles    []string           `bson:"roles,omitempty"`
+	Avatar   []byte             `bson:"avatar,omitempty"`
+	Address  *Address           `bson:"address,omitempty"`
+	Settings bson.D             `bson:"settings,omitempty"`
+	Deleted  bool               `bson:"deleted,omitempty"`
+
+	Timestamps `bson:",inline"`
+
+	Session string `bson:"-"`
+	cache   string
+}
+
+func mustObjectID(s string) primitive.ObjectID {
+	id, err := primitive.ObjectIDFromHex(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return id
+}
+
+var accountCreatedAt = time.Date(
+	2021, time.October, 27, 22, 30, 34, 0, time.UTC,
+)
+
+//
+// Test cases
+//
+
+var marshalingTestCases = []struct {
+	name string
+	v    interface{}
+}{
+	{
+		name: "empty struct",
+		v:    &Account{},
+	},
+	{
+		name: "full struct",
+		v: &Account{
+			ID:      mustObjectID("617a1d6e5c3b0a1b2c3d4e5f"),
+			Email:   "jane@example.com",
+			Logins:  1024,
+			Credits: 50,
+			Balance: 12.5,
+			Roles:   []string{"admin", "editor"},
+			Avatar:  []byte{0x89, 0x50, 0x4e, 0x47},
+			Address: &Address{Street: "1 Main St", City: "Springfield"},
+			Settings: bson.D{
+				{Key: "theme", Value: "dark"},
+				{Key: "page_size", Value: int32(25)},
+			},
+			Deleted: true,
+			Timestamps: Timestamps{
+				CreatedAt: accountCreatedAt,
+				UpdatedAt: accountCreatedAt.Add(90 * time.Minute),
+			},
+		},
+	},
+	{
+		name: "ordered document",
+		v: &bson.D{
+			{Key: "name", Value: "jane"},
+			{Key: "age", Value: int32(42)},
+			{Key: "score", Value: int64(9000000000)},
+			{Key: "tags", Value: bson.A{"a", "b"}},
+		},
+	},
+}
+
+var marshalingPTestCases = []struct {
+	name string
+	v    interface{}
+	want interface{}
+}{
+	{
+		name: "empty struct",
+		v:    &Account{},
+		want: &Account{},
+	},
+	{
+		name: "ignored fields",
+		v: &Account{
+			Email:   "jane@example.com",
+			Session: "2fd5af35",
+			cache:   "warm",
+		},
+		want: &Account{
+			Email: "jane@example.com",
+		},
+	},
+	{
+		name: "truncated time precision",
+		v: &Account{
+			Timestamps: Timestamps{
+				CreatedAt: accountCreatedAt.Add(123456789),
+			},
+		},
+		want: &Account{
+			Timestamps: Timestamps{
+				CreatedAt: accountCreatedAt.Add(123000000),
+			},
+		},
+	},
+}
+
+//
+// Tests
+//
+
+func TestBSONMarshaling(t *testing.T) {
+	for _, tt := range marshalingTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			BSONMarshaling(t, tt.v)
+		})
+	}
+}
+
+func TestBSONMarshalingP(t *testing.T) {
+	for _, tt := range marshalingPTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			BSONMarshalingP(t, tt.v, tt.want)
+		})
+	}
+}
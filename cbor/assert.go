@@ -0,0 +1,190 @@
+package cbor
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/jimeh/go-goldsert"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Assert holds both configuration and implementation of all CBOR assertion
+// methods.
+//
+// You can customize serialization by setting the EncMode, DecMode and
+// DiagMode fields to modes created from custom cbor.EncOptions,
+// cbor.DecOptions and cbor.DiagOptions.
+//
+// Golden files are handled by the Goldsert field, whose Golden field configures
+// golden file generation, and whose NormalizeLineBreaks field enables
+// line-break normalization of diagnostic notation golden files. Raw CBOR golden
+// files are never normalized. See the github.com/jimeh/go-goldsert package for
+// details about what can be configured.
+type Assert struct {
+	EncMode  cbor.EncMode
+	DecMode  cbor.DecMode
+	DiagMode cbor.DiagMode
+	Goldsert *goldsert.Assert
+}
+
+// New returns a new *Assert instance configured with default settings.
+//
+// The default encoder uses Core Deterministic Encoding, ensuring golden files
+// are stable. The default decoder prohibits unknown fields which are not
+// present on the provided struct. The default diagnostic notation shows byte
+// strings as text when they are valid UTF-8.
+func New() *Assert {
+	return &Assert{
+		EncMode:  mustEncMode(cbor.CoreDetEncOptions()),
+		DecMode:  mustDecMode(newDecOptions()),
+		DiagMode: mustDiagMode(newDiagOptions()),
+		Goldsert: goldsert.New(),
+	}
+}
+
+// CBORMarshaling asserts that the given "v" value CBOR marshals to an expected
+// value fetched from golden files on disk, and then verifies that the
+// marshaled result produces a value that is equal to "v" when unmarshaled.
+//
+// Used for objects that do NOT change when they are marshaled and unmarshaled.
+func (s *Assert) CBORMarshaling(t *testing.T, v interface{}) {
+	t.Helper()
+
+	s.CBORMarshalingP(t, v, v)
+}
+
+// CBORMarshalingP asserts that the given "v" value CBOR marshals to an expected
+// value fetched from golden files on disk, and then verifies that the
+// marshaled result produces a value that is equal to "want" when unmarshaled.
+//
+// Used for objects that change when they are marshaled and unmarshaled.
+func (s *Assert) CBORMarshalingP(t *testing.T, v, want interface{}) {
+	t.Helper()
+
+	marshaled, err := s.EncMode.Marshal(v)
+	require.NoErrorf(t, err, "failed to CBOR marshal %T: %+v", v, v)
+
+	diag, err := s.diagnose(marshaled)
+	require.NoErrorf(t, err, "failed to CBOR diagnose %T: %+v", v, v)
+
+	gold := s.Goldsert.GoldenBinaryP(t, "goldsert_cbor", marshaled, cborEqual)
+	goldDiag := s.Goldsert.GoldenP(t, "goldsert_cbor_edn", diag, nil)
+
+	goldRawDiag, err := s.diagnose(gold)
+	require.NoErrorf(t, err,
+		"failed to CBOR diagnose %s",
+		s.Goldsert.Golden.FileP(t, "goldsert_cbor"),
+	)
+	assert.Equalf(t, string(goldRawDiag), string(goldDiag),
+		"golden files %s and %s are out of sync",
+		s.Goldsert.Golden.FileP(t, "goldsert_cbor"),
+		s.Goldsert.Golden.FileP(t, "goldsert_cbor_edn"),
+	)
+
+	cborEq(t, gold, marshaled)
+	assert.Equal(t, string(goldDiag), string(diag))
+
+	if reflect.ValueOf(want).Kind() != reflect.Ptr {
+		require.FailNowf(t,
+			"only pointer types can be asserted",
+			"%T is not a pointer type", want,
+		)
+	}
+
+	got := reflect.New(reflect.TypeOf(want).Elem()).Interface()
+	err = s.DecMode.Unmarshal(gold, got)
+	require.NoErrorf(t, err,
+		"failed to CBOR unmarshal %T from %s",
+		got, s.Goldsert.Golden.FileP(t, "goldsert_cbor"),
+	)
+	assert.Equal(t, want, got,
+		"unmarshaling from golden file does not match expected object",
+	)
+}
+
+// cborEq asserts that two CBOR data items are semantically equal, by decoding
+// both into generic values and comparing them.
+func cborEq(t *testing.T, expected, actual []byte) {
+	t.Helper()
+
+	var expectedCBOR, actualCBOR interface{}
+	err := cbor.Unmarshal(expected, &expectedCBOR)
+	require.NoErrorf(t, err,
+		"Expected value ('%x') is not valid cbor.\nCBOR parsing error: '%s'",
+		expected, err,
+	)
+	err = cbor.Unmarshal(actual, &actualCBOR)
+	require.NoErrorf(t, err,
+		"Input ('%x') needs to be valid cbor.\nCBOR parsing error: '%s'",
+		actual, err,
+	)
+
+	assert.Equal(t, expectedCBOR, actualCBOR)
+}
+
+// cborEqual returns true if both CBOR data items decode into equal generic
+// values.
+func cborEqual(gold, data []byte) bool {
+	var a, b interface{}
+	if cbor.Unmarshal(gold, &a) != nil || cbor.Unmarshal(data, &b) != nil {
+		return false
+	}
+
+	return assert.ObjectsAreEqual(a, b)
+}
+
+// diagnose returns the diagnostic notation of the given CBOR data item,
+// terminated by a newline.
+func (s *Assert) diagnose(data []byte) ([]byte, error) {
+	diag, err := s.DiagMode.Diagnose(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(diag + "\n"), nil
+}
+
+// newDecOptions returns the default decoding options used by Assert, which
+// disallow unknown fields.
+func newDecOptions() cbor.DecOptions {
+	return cbor.DecOptions{
+		ExtraReturnErrors: cbor.ExtraDecErrorUnknownField,
+	}
+}
+
+// newDiagOptions returns the default diagnostic notation options used by
+// Assert, which notate byte strings as text when they are valid UTF-8.
+func newDiagOptions() cbor.DiagOptions {
+	return cbor.DiagOptions{
+		ByteStringText: true,
+	}
+}
+
+func mustEncMode(opts cbor.EncOptions) cbor.EncMode {
+	em, err := opts.EncMode()
+	if err != nil {
+		panic(err)
+	}
+
+	return em
+}
+
+func mustDecMode(opts cbor.DecOptions) cbor.DecMode {
+	dm, err := opts.DecMode()
+	if err != nil {
+		panic(err)
+	}
+
+	return dm
+}
+
+func mustDiagMode(opts cbor.DiagOptions) cbor.DiagMode {
+	dm, err := opts.DiagMode()
+	if err != nil {
+		panic(err)
+	}
+
+	return dm
+}
@@ -0,0 +1,25 @@
+package cbor
+
+import (
+	"testing"
+)
+
+func TestAssert_CBORMarshaling(t *testing.T) {
+	for _, tt := range marshalingTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+
+			gs.CBORMarshaling(t, tt.v)
+		})
+	}
+}
+
+func TestAssert_CBORMarshalingP(t *testing.T) {
+	for _, tt := range marshalingPTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+
+			gs.CBORMarshalingP(t, tt.v, tt.want)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Package cbor provides goldsert test helpers for CBOR (RFC 8949), which use
+// golden files to assert marshaling and unmarshaling of given objects.
+//
+// It lives in its own module so that the core goldsert module does not depend
+// on a CBOR library.
+//
+// As CBOR is a binary format, each assertion uses two golden files; one with
+// the raw CBOR bytes, and one with the same data in human readable Extended
+// Diagnostic Notation (EDN), making changes easy to review.
+//
+// Each test helper operates in two stages:
+//
+// Firstly they marshal the provided object to CBOR and reads the corresponding
+// golden files from disk, followed by verifying the raw bytes are semantically
+// identical, and that the diagnostic notation of both is identical.
+//
+// Secondly they unmarshal the raw bytes from the golden file, and verifies that
+// the result is identical to the original object.
+//
+// Usage
+//
+//  func TestReadingMarshaling(t *testing.T) {
+//      cbor.CBORMarshaling(t, &Reading{Sensor: "temp", Value: 21.5})
+//  }
+//
+// The above example will read from the following golden files:
+//
+//  testdata/TestReadingMarshaling/goldsert_cbor.golden
+//  testdata/TestReadingMarshaling/goldsert_cbor_edn.golden
+//
+// To create/update golden files, simply set the GOLDEN_UPDATE environment
+// variable to one of "1", "y", "t", "yes", "on", or "true" when running tests.
+package cbor
+
+import (
+	"testing"
+)
+
+var global = New()
+
+// CBORMarshaling asserts that the given "v" value CBOR marshals to an expected
+// value fetched from golden files on disk, and then verifies that the
+// marshaled result produces a value that is equal to "v" when unmarshaled.
+//
+// Used for objects that do NOT change when they are marshaled and unmarshaled.
+func CBORMarshaling(t *testing.T, v interface{}) {
+	t.Helper()
+
+	global.CBORMarshaling(t, v)
+}
+
+// CBORMarshalingP asserts that the given "v" value CBOR marshals to an expected
+// value fetched from golden files on disk, and then verifies that the
+// marshaled result produces a value that is equal to "want" when unmarshaled.
+//
+// Used for objects that change when they are marshaled and unmarshaled.
+func CBORMarshalingP(t *testing.T, v, want interface{}) {
+	t.Helper()
+
+	global.CBORMarshalingP(t, v, want)
+}
@@ -0,0 +1,159 @@
+package cbor
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+//
+// Helpers
+//
+
+type Sensor struct {
+	ID       string            `cbor:"id"`
+	Name     string            `cbor:"name,omitempty"`
+	Location *Location         `cbor:"location,omitempty"`
+	Labels   map[string]string `cbor:"labels,omitempty"`
+}
+
+type Location struct {
+	Lat float64 `cbor:"lat"`
+	Lng float64 `cbor:"lng"`
+}
+
+type Reading struct {
+	Sensor    string  `cbor:"1,keyasint"`
+	Value     float64 `cbor:"2,keyasint"`
+	Raw       []byte  `cbor:"3,keyasint,omitempty"`
+	Sequence  uint64  `cbor:"4,keyasint,omitempty"`
+	Confirmed bool    `cbor:"5,keyasint,omitempty"`
+
+	Calibrated float64 `cbor:"-"`
+	order      int
+}
+
+// Firmware implements encoding.BinaryMarshaler and
+// encoding.BinaryUnmarshaler to store itself as a "major.minor" byte string.
+type Firmware struct {
+	Major int
+	Minor int
+}
+
+func (s *Firmware) MarshalBinary() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d.%d", s.Major, s.Minor)), nil
+}
+
+func (s *Firmware) UnmarshalBinary(data []byte) error {
+	_, err := fmt.Sscanf(strings.TrimSpace(string(data)), "%d.%d",
+		&s.Major, &s.Minor,
+	)
+
+	return err
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+//
+// Test cases
+//
+
+var marshalingTestCases = []struct {
+	name string
+	v    interface{}
+}{
+	{
+		name: "true bool pointer",
+		v:    boolPtr(true),
+	},
+	{
+		name: "int pointer",
+		v:    intPtr(42),
+	},
+	{
+		name: "string pointer",
+		v:    stringPtr("hello world"),
+	},
+	{
+		name: "empty struct",
+		v:    &Sensor{},
+	},
+	{
+		name: "full struct",
+		v: &Sensor{
+			ID:       "a4c1b3f0",
+			Name:     "Greenhouse",
+			Location: &Location{Lat: 51.5007, Lng: -0.1246},
+			Labels:   map[string]string{"zone": "b", "floor": "1"},
+		},
+	},
+	{
+		name: "integer keys",
+		v: &Reading{
+			Sensor:    "a4c1b3f0",
+			Value:     21.5,
+			Raw:       []byte{0x01, 0x02, 0xff},
+			Sequence:  1024,
+			Confirmed: true,
+		},
+	},
+	{
+		name: "custom marshaling",
+		v:    &Firmware{Major: 4, Minor: 2},
+	},
+}
+
+var marshalingPTestCases = []struct {
+	name string
+	v    interface{}
+	want interface{}
+}{
+	{
+		name: "empty struct",
+		v:    &Reading{},
+		want: &Reading{},
+	},
+	{
+		name: "ignored fields",
+		v: &Reading{
+			Sensor:     "a4c1b3f0",
+			Value:      21.5,
+			Calibrated: 21.7,
+			order:      3,
+		},
+		want: &Reading{
+			Sensor: "a4c1b3f0",
+			Value:  21.5,
+		},
+	},
+}
+
+//
+// Tests
+//
+
+func TestCBORMarshaling(t *testing.T) {
+	for _, tt := range marshalingTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			CBORMarshaling(t, tt.v)
+		})
+	}
+}
+
+func TestCBORMarshalingP(t *testing.T) {
+	for _, tt := range marshalingPTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			CBORMarshalingP(t, tt.v, tt.want)
+		})
+	}
+}
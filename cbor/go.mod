@@ -0,0 +1,17 @@
+module github.com/jimeh/go-goldsert/cbor
+
+go 1.17
+
+require (
+	github.com/fxamacker/cbor/v2 v2.7.0
+	github.com/jimeh/go-goldsert v0.2.0
+	github.com/stretchr/testify v1.7.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/jimeh/go-golden v0.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
+	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b // indirect
+)
@@ -0,0 +1,57 @@
+package goldsert
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// EnvCIFunc reports if tests run in a CI environment, as detected by the CI
+// environment variable being set to a value other than "0", "n", "f", "no",
+// "off", or "false". To detect CI environments by other environment variables,
+// set GOLDSERT_CI_ENV to a comma-separated list of their names, like
+// "CI,JENKINS_URL".
+//
+// This is the default CIFunc of Assert.
+func EnvCIFunc() bool {
+	names := "CI"
+	if env := os.Getenv("GOLDSERT_CI_ENV"); env != "" {
+		names = env
+	}
+
+	for _, name := range strings.Split(names, ",") {
+		v := os.Getenv(strings.TrimSpace(name))
+		v = strings.ToLower(strings.TrimSpace(v))
+		if v != "" && !containsString(falsyStrings, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// refuseCIUpdate fails the test when golden files are about to be updated in
+// a CI environment, as reported by CIFunc, unless the GOLDSERT_ALLOW_CI_UPDATE
+// environment variable is set to one of "1", "y", "t", "yes", "on", or "true".
+func (s *Assert) refuseCIUpdate(t *testing.T) {
+	t.Helper()
+
+	if s.ciUpdateRefused() {
+		require.FailNow(t,
+			"refusing to update golden files in CI",
+			"update mode is enabled in a CI environment, which would make "+
+				"golden file assertions pass without checking anything; set "+
+				"GOLDSERT_ALLOW_CI_UPDATE=1 to update golden files anyway",
+		)
+	}
+}
+
+func (s *Assert) ciUpdateRefused() bool {
+	if s.CIFunc == nil || !s.CIFunc() {
+		return false
+	}
+
+	return !containsString(truthyStrings, os.Getenv("GOLDSERT_ALLOW_CI_UPDATE"))
+}
@@ -0,0 +1,128 @@
+package goldsert
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// setenv sets the given environment variables for the duration of the test,
+// unsetting those with empty values.
+func setenv(t *testing.T, env map[string]string) {
+	t.Helper()
+
+	for k, v := range env {
+		k := k
+		prev, ok := os.LookupEnv(k)
+		t.Cleanup(func() {
+			if ok {
+				os.Setenv(k, prev)
+			} else {
+				os.Unsetenv(k)
+			}
+		})
+
+		if v == "" {
+			os.Unsetenv(k)
+		} else {
+			os.Setenv(k, v)
+		}
+	}
+}
+
+func TestEnvCIFunc(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{
+			name: "unset",
+			env:  map[string]string{"CI": "", "GOLDSERT_CI_ENV": ""},
+			want: false,
+		},
+		{
+			name: "true",
+			env:  map[string]string{"CI": "true", "GOLDSERT_CI_ENV": ""},
+			want: true,
+		},
+		{
+			name: "one",
+			env:  map[string]string{"CI": "1", "GOLDSERT_CI_ENV": ""},
+			want: true,
+		},
+		{
+			name: "false",
+			env:  map[string]string{"CI": "False", "GOLDSERT_CI_ENV": ""},
+			want: false,
+		},
+		{
+			name: "custom variable set",
+			env: map[string]string{
+				"CI":              "",
+				"GOLDSERT_CI_ENV": "GOLDSERT_TEST_CI, BUILD_URL",
+				"BUILD_URL":       "https://ci.example.com/build/42",
+			},
+			want: true,
+		},
+		{
+			name: "custom variable unset",
+			env: map[string]string{
+				"CI":               "true",
+				"GOLDSERT_CI_ENV":  "GOLDSERT_TEST_CI",
+				"GOLDSERT_TEST_CI": "",
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setenv(t, tt.env)
+
+			assert.Equal(t, tt.want, EnvCIFunc())
+		})
+	}
+}
+
+func TestAssert_ciUpdateRefused(t *testing.T) {
+	tests := []struct {
+		name   string
+		ciFunc func() bool
+		allow  string
+		want   bool
+	}{
+		{name: "no detection", ciFunc: nil, want: false},
+		{
+			name:   "not in CI",
+			ciFunc: func() bool { return false },
+			want:   false,
+		},
+		{
+			name:   "in CI",
+			ciFunc: func() bool { return true },
+			want:   true,
+		},
+		{
+			name:   "in CI with invalid override",
+			ciFunc: func() bool { return true },
+			allow:  "please",
+			want:   true,
+		},
+		{
+			name:   "in CI with override",
+			ciFunc: func() bool { return true },
+			allow:  "1",
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setenv(t, map[string]string{"GOLDSERT_ALLOW_CI_UPDATE": tt.allow})
+			gs := New()
+			gs.CIFunc = tt.ciFunc
+
+			assert.Equal(t, tt.want, gs.ciUpdateRefused())
+		})
+	}
+}
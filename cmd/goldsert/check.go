@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	errInvalidJSON   = errors.New("invalid JSON")
+	errNoXMLRoot     = errors.New("no root element")
+	errInvalidFormKV = errors.New("line is not a key=value pair")
+)
+
+// checkers validate the content of golden files per format. Formats without a
+// checker, like binary formats and dumps, are not checked.
+var checkers = map[string]func([]byte) error{
+	"json":         checkJSON,
+	"schema":       checkJSON,
+	"protojson":    checkJSON,
+	"msgpack_json": checkJSON,
+	"bson":         checkJSON,
+	"jsonl":        checkJSONLines,
+	"yaml":         checkYAML,
+	"yaml_docs":    checkYAML,
+	"xml":          checkXML,
+	"form":         checkForm,
+}
+
+// check checks that golden files parse as their format.
+func (c *cli) check(args []string) int {
+	var opts options
+	fs := c.flags("check", &opts)
+	verbose := fs.Bool("v", false, "also list valid and unchecked files")
+	files, code, ok := c.parse(fs, args, &opts)
+	if !ok {
+		return code
+	}
+
+	var invalid, unchecked int
+	for _, f := range files {
+		checker, ok := checkers[f.Format]
+		if !ok {
+			unchecked++
+			if *verbose {
+				fmt.Fprintf(c.stdout, "%s: unchecked\n", f.Path)
+			}
+
+			continue
+		}
+
+		data, err := ioutil.ReadFile(f.Path)
+		if err == nil {
+			err = checker(data)
+		}
+		if err != nil {
+			invalid++
+			fmt.Fprintf(c.stdout, "%s: %s\n", f.Path, err)
+		} else if *verbose {
+			fmt.Fprintf(c.stdout, "%s: ok\n", f.Path)
+		}
+	}
+
+	fmt.Fprintf(c.stdout, "%d golden files, %d invalid, %d unchecked\n",
+		len(files), invalid, unchecked,
+	)
+	if invalid > 0 {
+		return 1
+	}
+
+	return 0
+}
+
+func checkJSON(data []byte) error {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if err := dec.Decode(&v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: unexpected data after top-level value",
+			errInvalidJSON,
+		)
+	}
+
+	return nil
+}
+
+func checkJSONLines(data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for n := 1; scanner.Scan(); n++ {
+		if !json.Valid(scanner.Bytes()) {
+			return fmt.Errorf("%w on line %d", errInvalidJSON, n)
+		}
+	}
+
+	return scanner.Err()
+}
+
+func checkYAML(data []byte) error {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var node yaml.Node
+		err := dec.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func checkXML(data []byte) error {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	root := false
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if _, ok := tok.(xml.StartElement); ok {
+			root = true
+		}
+	}
+	if !root {
+		return errNoXMLRoot
+	}
+
+	return nil
+}
+
+func checkForm(data []byte) error {
+	for n, line := range strings.Split(string(data), "\n") {
+		if line == "" {
+			continue
+		}
+		if !strings.Contains(line, "=") {
+			return fmt.Errorf("%w on line %d", errInvalidFormKV, n+1)
+		}
+		if _, err := url.ParseQuery(line); err != nil {
+			return fmt.Errorf("line %d: %w", n+1, err)
+		}
+	}
+
+	return nil
+}
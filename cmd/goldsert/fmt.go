@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	errXMLMixedContent = errors.New("mixed content is not supported")
+	errXMLMismatch     = errors.New("mismatched end element")
+)
+
+// formatters re-indent the content of golden files per format, matching the
+// output of the default encoders of goldsert. Formats without a formatter are
+// left as they are.
+var formatters = map[string]func([]byte) ([]byte, error){
+	"json":      formatJSON,
+	"schema":    formatJSON,
+	"jsonl":     formatJSONLines,
+	"yaml":      formatYAML,
+	"yaml_docs": formatYAML,
+	"xml":       formatXML,
+}
+
+// format re-indents golden files with the default encoder settings.
+func (c *cli) format(args []string) int {
+	var opts options
+	fs := c.flags("fmt", &opts)
+	listOnly := fs.Bool("l", false,
+		"only list files whose formatting differs, without writing them",
+	)
+	files, code, ok := c.parse(fs, args, &opts)
+	if !ok {
+		return code
+	}
+
+	code = 0
+	for _, f := range files {
+		formatter, ok := formatters[f.Format]
+		if !ok {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(f.Path)
+		if err != nil {
+			fmt.Fprintf(c.stderr, "goldsert: %s\n", err)
+			code = 1
+
+			continue
+		}
+
+		formatted, err := formatter(data)
+		if err != nil {
+			fmt.Fprintf(c.stderr, "goldsert: %s: %s\n", f.Path, err)
+			code = 1
+
+			continue
+		}
+		if bytes.Equal(data, formatted) {
+			continue
+		}
+
+		fmt.Fprintln(c.stdout, f.Path)
+		if *listOnly {
+			continue
+		}
+
+		err = ioutil.WriteFile(f.Path, formatted, 0o644)
+		if err != nil {
+			fmt.Fprintf(c.stderr, "goldsert: %s\n", err)
+			code = 1
+		}
+	}
+
+	return code
+}
+
+func formatJSON(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	err := json.Indent(&buf, bytes.TrimSpace(data), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteString("\n")
+
+	return buf.Bytes(), nil
+}
+
+func formatJSONLines(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	for n, line := range strings.Split(string(data), "\n") {
+		if line == "" {
+			continue
+		}
+		if err := json.Compact(&buf, []byte(line)); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n+1, err)
+		}
+		buf.WriteString("\n")
+	}
+
+	return buf.Bytes(), nil
+}
+
+func formatYAML(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	docs := 0
+	for ; ; docs++ {
+		var node yaml.Node
+		err := dec.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := enc.Encode(&node); err != nil {
+			return nil, err
+		}
+	}
+	if docs == 0 {
+		return data, nil
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// xmlNode is a node of an XML document, as needed to re-indent it.
+type xmlNode struct {
+	// raw holds the rendered form of comments, processing instructions and
+	// directives.
+	raw string
+
+	name     string
+	attrs    []xml.Attr
+	children []*xmlNode
+	text     string
+}
+
+// formatXML re-indents an XML document with two spaces, as the default XML
+// encoder does. Element and attribute names are kept as they are, including
+// namespace prefixes. Documents with mixed content are not supported.
+//
+// Documents which are not indented at all are returned as they are, as they
+// are typically written by an encoder without indentation, or canonicalized,
+// where whitespace is significant.
+func formatXML(data []byte) ([]byte, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	root := &xmlNode{}
+	stack := []*xmlNode{root}
+	indented := false
+	for {
+		tok, err := dec.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		parent := stack[len(stack)-1]
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			n := &xmlNode{name: xmlName(tok.Name), attrs: tok.Attr}
+			parent.children = append(parent.children, n)
+			stack = append(stack, n)
+		case xml.EndElement:
+			if len(stack) == 1 || parent.name != xmlName(tok.Name) {
+				return nil, fmt.Errorf("%w: </%s>",
+					errXMLMismatch, xmlName(tok.Name),
+				)
+			}
+			if len(parent.children) > 0 &&
+				strings.TrimSpace(parent.text) != "" {
+				return nil, fmt.Errorf("%w: <%s>",
+					errXMLMixedContent, parent.name,
+				)
+			}
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 1 && bytes.Contains(tok, []byte("\n")) &&
+				len(bytes.TrimSpace(tok)) == 0 {
+				indented = true
+			}
+			parent.text += string(tok)
+		case xml.Comment:
+			parent.children = append(parent.children,
+				&xmlNode{raw: "<!--" + string(tok) + "-->"},
+			)
+		case xml.ProcInst:
+			raw := "<?" + tok.Target + " " + string(tok.Inst) + "?>"
+			parent.children = append(parent.children, &xmlNode{raw: raw})
+		case xml.Directive:
+			parent.children = append(parent.children,
+				&xmlNode{raw: "<!" + string(tok) + ">"},
+			)
+		}
+	}
+	if len(stack) > 1 {
+		return nil, fmt.Errorf("%w: <%s> is not closed",
+			errXMLMismatch, stack[len(stack)-1].name,
+		)
+	}
+	if !indented {
+		return data, nil
+	}
+
+	var buf bytes.Buffer
+	for i, n := range root.children {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		writeXMLNode(&buf, n, 0)
+	}
+	if bytes.HasSuffix(data, []byte("\n")) {
+		buf.WriteString("\n")
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeXMLNode(buf *bytes.Buffer, n *xmlNode, depth int) {
+	indent := strings.Repeat("  ", depth)
+	buf.WriteString(indent)
+	if n.name == "" {
+		buf.WriteString(n.raw)
+
+		return
+	}
+
+	buf.WriteString("<" + n.name)
+	for _, attr := range n.attrs {
+		buf.WriteString(" " + xmlName(attr.Name) + "=\"")
+		_ = xml.EscapeText(buf, []byte(attr.Value))
+		buf.WriteString("\"")
+	}
+	buf.WriteString(">")
+
+	if len(n.children) == 0 {
+		_ = xml.EscapeText(buf, []byte(n.text))
+	} else {
+		for _, child := range n.children {
+			buf.WriteString("\n")
+			writeXMLNode(buf, child, depth+1)
+		}
+		buf.WriteString("\n" + indent)
+	}
+	buf.WriteString("</" + n.name + ">")
+}
+
+func xmlName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+
+	return name.Space + ":" + name.Local
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	goldenPrefix = "goldsert_"
+	testdataDir  = "testdata"
+)
+
+// goldenFile is a golden file of a go-goldsert test helper.
+type goldenFile struct {
+	// Path is the path of the file, as found within the searched paths.
+	Path string
+
+	// Package is the directory of the package owning the testdata directory
+	// the file was found in.
+	Package string
+
+	// Test is the name of the test, including subtests, which the file
+	// belongs to.
+	Test string
+
+	// Format is the format of the file, as given by its name, like "json" for
+	// goldsert_json.golden.
+	Format string
+
+	// Size is the size of the file in bytes.
+	Size int64
+}
+
+// findGoldenFiles returns all golden files with the given suffix found within
+// testdata directories under the given paths, sorted by path. Hidden
+// directories and vendor directories are skipped.
+func findGoldenFiles(paths []string, suffix string) ([]*goldenFile, error) {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	seen := map[string]bool{}
+	var files []*goldenFile
+	for _, root := range paths {
+		err := filepath.Walk(root,
+			func(path string, info os.FileInfo, err error) error {
+				if err != nil {
+					return err
+				}
+				if info.IsDir() {
+					if path != root && skipDir(info.Name()) {
+						return filepath.SkipDir
+					}
+
+					return nil
+				}
+
+				f := parseGoldenPath(path, suffix)
+				if f == nil || seen[filepath.Clean(path)] {
+					return nil
+				}
+				seen[filepath.Clean(path)] = true
+				f.Size = info.Size()
+				files = append(files, f)
+
+				return nil
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+
+	return files, nil
+}
+
+func skipDir(name string) bool {
+	return name == "vendor" ||
+		(strings.HasPrefix(name, ".") && name != "." && name != "..")
+}
+
+// parseGoldenPath returns a goldenFile for the given path, or nil if it is
+// not the path of a golden file within a testdata directory.
+func parseGoldenPath(path, suffix string) *goldenFile {
+	base := filepath.Base(path)
+	if !strings.HasPrefix(base, goldenPrefix) ||
+		!strings.HasSuffix(base, suffix) ||
+		len(base) <= len(goldenPrefix)+len(suffix) {
+		return nil
+	}
+
+	parts := strings.Split(filepath.ToSlash(filepath.Clean(path)), "/")
+	i := len(parts) - 2
+	for i >= 0 && parts[i] != testdataDir {
+		i--
+	}
+	if i < 0 {
+		return nil
+	}
+
+	pkg := strings.Join(parts[:i], "/")
+	if pkg == "" {
+		pkg = "."
+	}
+
+	return &goldenFile{
+		Path:    path,
+		Package: pkg,
+		Test:    strings.Join(parts[i+1:len(parts)-1], "/"),
+		Format: strings.TrimSuffix(
+			strings.TrimPrefix(base, goldenPrefix), suffix,
+		),
+	}
+}
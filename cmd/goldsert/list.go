@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+	"text/tabwriter"
+)
+
+// list lists golden files with their package, test and format.
+func (c *cli) list(args []string) int {
+	var opts options
+	fs := c.flags("list", &opts)
+	files, code, ok := c.parse(fs, args, &opts)
+	if !ok {
+		return code
+	}
+
+	w := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "PATH\tPACKAGE\tTEST\tFORMAT")
+	for _, f := range files {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", f.Path, f.Package, f.Test, f.Format)
+	}
+	w.Flush()
+
+	return 0
+}
@@ -0,0 +1,188 @@
+// Command goldsert works with the golden files of go-goldsert test helpers
+// across the testdata directories of a repository.
+//
+// Usage:
+//
+//	goldsert <command> [flags] [path ...]
+//
+// Commands:
+//
+//	list   list golden files with their package, test and format
+//	check  check that golden files parse as their format
+//	fmt    re-indent golden files with the default encoder settings
+//	review review pending golden files written in review mode
+//	stats  report golden file counts and sizes per package and format
+//
+// Paths default to the current directory, and are searched recursively for
+// golden files named goldsert_<format>.golden within testdata directories.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// command is a subcommand of the goldsert CLI.
+type command struct {
+	summary string
+	run     func(c *cli, args []string) int
+}
+
+// commands returns the subcommands of the goldsert CLI, keyed by name.
+func commands() map[string]*command {
+	return map[string]*command{
+		"list": {
+			summary: "list golden files with their package, test and format",
+			run:     (*cli).list,
+		},
+		"check": {
+			summary: "check that golden files parse as their format",
+			run:     (*cli).check,
+		},
+		"fmt": {
+			summary: "re-indent golden files with the default encoder settings",
+			run:     (*cli).format,
+		},
+		"review": {
+			summary: "review pending golden files written in review mode",
+			run:     (*cli).review,
+		},
+		"stats": {
+			summary: "report golden file counts and sizes per package and " +
+				"format",
+			run: (*cli).stats,
+		},
+	}
+}
+
+// cli holds the input and output streams of the goldsert CLI.
+type cli struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func main() {
+	c := &cli{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}
+
+	os.Exit(c.run(os.Args[1:]))
+}
+
+// run runs the command given by args, returning the exit code.
+func (c *cli) run(args []string) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" ||
+		args[0] == "help" {
+		c.usage(c.stdout)
+
+		return 0
+	}
+
+	cmd, ok := commands()[args[0]]
+	if !ok {
+		fmt.Fprintf(c.stderr, "goldsert: unknown command %q\n\n", args[0])
+		c.usage(c.stderr)
+
+		return 2
+	}
+
+	return cmd.run(c, args[1:])
+}
+
+func (c *cli) usage(w io.Writer) {
+	cmds := commands()
+	names := make([]string, 0, len(cmds))
+	for name := range cmds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "Usage: goldsert <command> [flags] [path ...]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-7s%s\n", name, cmds[name].summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'goldsert <command> -h' for command flags.")
+}
+
+// options holds the flags shared by all commands.
+type options struct {
+	suffix  string
+	formats string
+}
+
+// flags returns a flag set for the named command, with the flags shared by
+// all commands.
+func (c *cli) flags(name string, opts *options) *flag.FlagSet {
+	fs := flag.NewFlagSet("goldsert "+name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.StringVar(&opts.suffix, "suffix", ".golden", "golden file name suffix")
+	fs.StringVar(&opts.formats, "formats", "",
+		"comma-separated list of formats to limit golden files to, "+
+			"like \"json,yaml\"",
+	)
+	fs.Usage = func() {
+		summary := commands()[name].summary
+		fmt.Fprintf(c.stderr, "Usage: goldsert %s [flags] [path ...]\n\n%s\n\n",
+			name, strings.ToUpper(summary[:1])+summary[1:],
+		)
+		fmt.Fprintln(c.stderr, "Flags:")
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+// parse parses command line flags, and finds golden files within the
+// remaining path arguments. It returns false with the exit code to use if
+// parsing or finding golden files failed.
+func (c *cli) parse(
+	fs *flag.FlagSet,
+	args []string,
+	opts *options,
+) ([]*goldenFile, int, bool) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil, 0, false
+		}
+
+		return nil, 2, false
+	}
+
+	return c.find(fs.Args(), opts)
+}
+
+// find finds golden files within the given paths, limited to the formats of
+// opts. It returns false with the exit code to use if finding golden files
+// failed.
+func (c *cli) find(paths []string, opts *options) ([]*goldenFile, int, bool) {
+	files, err := findGoldenFiles(paths, opts.suffix)
+	if err != nil {
+		fmt.Fprintf(c.stderr, "goldsert: %s\n", err)
+
+		return nil, 1, false
+	}
+
+	if opts.formats == "" {
+		return files, 0, true
+	}
+
+	formats := map[string]bool{}
+	for _, f := range strings.Split(opts.formats, ",") {
+		formats[strings.TrimSpace(f)] = true
+	}
+	filtered := files[:0]
+	for _, f := range files {
+		if formats[f.Format] {
+			filtered = append(filtered, f)
+		}
+	}
+
+	return filtered, 0, true
+}
@@ -0,0 +1,373 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRepo is a repository with golden files, keyed by their path.
+var testRepo = map[string]string{
+	"testdata/TestBook/full/goldsert_json.golden": `{"id":"1","tags":["a"]}`,
+	"testdata/TestBook/full/goldsert_yaml.golden": "id:   \"1\"\ntags: [a]\n",
+	"testdata/TestBook/full/goldsert_xml.golden": "<Book id=\"1\">\n" +
+		"<tags>a &amp; b</tags></Book>\n",
+	"testdata/TestBook/goldsert_dump.golden":       "&Book{}\n",
+	"pkg/testdata/TestOrder/goldsert_jsonl.golden": "{\"id\": 1}\n{\"id\":2}\n",
+	"pkg/testdata/TestOrder/goldsert_toml.golden":  "id = 1\n",
+	"pkg/testdata/TestBroken/goldsert_json.golden": "{\"id\":\n",
+	"pkg/testdata/TestBroken/goldsert_form.golden": "id=1\nname\n",
+	"pkg/testdata/TestOrder/notes.txt":             "not a golden file\n",
+	"vendor/x/testdata/TestX/goldsert_json.golden": "{}\n",
+	".git/testdata/TestX/goldsert_json.golden":     "{}\n",
+	"docs/goldsert_json.golden":                    "{}\n",
+}
+
+func newTestRepo(t *testing.T) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "goldsert-cli-")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	for name, content := range testRepo {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0o644))
+	}
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	return dir
+}
+
+func runCLI(args ...string) (int, string, string) {
+	return runCLIWithInput("", args...)
+}
+
+func runCLIWithInput(input string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	c := &cli{
+		stdin:  strings.NewReader(input),
+		stdout: &stdout,
+		stderr: &stderr,
+	}
+	code := c.run(args)
+
+	return code, stdout.String(), stderr.String()
+}
+
+func TestCLI_run(t *testing.T) {
+	code, stdout, _ := runCLI()
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "Usage: goldsert <command>")
+
+	code, _, stderr := runCLI("nope")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "unknown command \"nope\"")
+
+	code, _, stderr = runCLI("list", "-nope")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "flag provided but not defined: -nope")
+
+	code, _, stderr = runCLI("list", "-h")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stderr, "Usage: goldsert list [flags] [path ...]")
+}
+
+// tableRows splits tabular output into rows of fields.
+func tableRows(output string) [][]string {
+	var rows [][]string
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		rows = append(rows, strings.Fields(line))
+	}
+
+	return rows
+}
+
+func TestCLI_list(t *testing.T) {
+	newTestRepo(t)
+
+	code, stdout, stderr := runCLI("list")
+
+	assert.Equal(t, 0, code)
+	assert.Empty(t, stderr)
+	assert.Equal(t, [][]string{
+		{"PATH", "PACKAGE", "TEST", "FORMAT"},
+		{
+			"pkg/testdata/TestBroken/goldsert_form.golden",
+			"pkg", "TestBroken", "form",
+		},
+		{
+			"pkg/testdata/TestBroken/goldsert_json.golden",
+			"pkg", "TestBroken", "json",
+		},
+		{
+			"pkg/testdata/TestOrder/goldsert_jsonl.golden",
+			"pkg", "TestOrder", "jsonl",
+		},
+		{
+			"pkg/testdata/TestOrder/goldsert_toml.golden",
+			"pkg", "TestOrder", "toml",
+		},
+		{
+			"testdata/TestBook/full/goldsert_json.golden",
+			".", "TestBook/full", "json",
+		},
+		{
+			"testdata/TestBook/full/goldsert_xml.golden",
+			".", "TestBook/full", "xml",
+		},
+		{
+			"testdata/TestBook/full/goldsert_yaml.golden",
+			".", "TestBook/full", "yaml",
+		},
+		{
+			"testdata/TestBook/goldsert_dump.golden",
+			".", "TestBook", "dump",
+		},
+	}, tableRows(stdout))
+}
+
+func TestCLI_list_formats(t *testing.T) {
+	newTestRepo(t)
+
+	code, stdout, _ := runCLI("list", "-formats", "json, toml", "pkg")
+
+	assert.Equal(t, 0, code)
+	assert.Equal(t, [][]string{
+		{"PATH", "PACKAGE", "TEST", "FORMAT"},
+		{
+			"pkg/testdata/TestBroken/goldsert_json.golden",
+			"pkg", "TestBroken", "json",
+		},
+		{
+			"pkg/testdata/TestOrder/goldsert_toml.golden",
+			"pkg", "TestOrder", "toml",
+		},
+	}, tableRows(stdout))
+}
+
+func TestCLI_check(t *testing.T) {
+	newTestRepo(t)
+
+	code, stdout, _ := runCLI("check")
+
+	assert.Equal(t, 1, code)
+	assert.Equal(t, ""+
+		"pkg/testdata/TestBroken/goldsert_form.golden: "+
+		"line is not a key=value pair on line 2\n"+
+		"pkg/testdata/TestBroken/goldsert_json.golden: unexpected EOF\n"+
+		"8 golden files, 2 invalid, 2 unchecked\n",
+		stdout,
+	)
+
+	code, stdout, _ = runCLI("check", "-v", "testdata")
+
+	assert.Equal(t, 0, code)
+	assert.Equal(t, ""+
+		"testdata/TestBook/full/goldsert_json.golden: ok\n"+
+		"testdata/TestBook/full/goldsert_xml.golden: ok\n"+
+		"testdata/TestBook/full/goldsert_yaml.golden: ok\n"+
+		"testdata/TestBook/goldsert_dump.golden: unchecked\n"+
+		"4 golden files, 0 invalid, 1 unchecked\n",
+		stdout,
+	)
+}
+
+func TestCLI_format(t *testing.T) {
+	dir := newTestRepo(t)
+	canonical := "<Book xmlns=\"urn:b\" id=\"1\"><tags>a</tags></Book>"
+	require.NoError(t, ioutil.WriteFile(
+		filepath.Join(dir, "testdata/TestBook/goldsert_xml.golden"),
+		[]byte(canonical), 0o644,
+	))
+
+	code, stdout, _ := runCLI("fmt", "-l", "testdata")
+
+	assert.Equal(t, 0, code)
+	assert.Equal(t, ""+
+		"testdata/TestBook/full/goldsert_json.golden\n"+
+		"testdata/TestBook/full/goldsert_xml.golden\n"+
+		"testdata/TestBook/full/goldsert_yaml.golden\n",
+		stdout,
+	)
+	data, err := ioutil.ReadFile(
+		filepath.Join(dir, "testdata/TestBook/full/goldsert_json.golden"),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, testRepo["testdata/TestBook/full/goldsert_json.golden"],
+		string(data),
+	)
+
+	code, stdout, stderr := runCLI("fmt")
+
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "pkg/testdata/TestBroken/goldsert_json.golden")
+	assert.Contains(t, stdout, "pkg/testdata/TestOrder/goldsert_jsonl.golden")
+
+	want := map[string]string{
+		"testdata/TestBook/full/goldsert_json.golden": "{\n" +
+			"  \"id\": \"1\",\n" +
+			"  \"tags\": [\n" +
+			"    \"a\"\n" +
+			"  ]\n" +
+			"}\n",
+		"testdata/TestBook/full/goldsert_yaml.golden": "id: \"1\"\n" +
+			"tags: [a]\n",
+		"testdata/TestBook/full/goldsert_xml.golden": "<Book id=\"1\">\n" +
+			"  <tags>a &amp; b</tags>\n" +
+			"</Book>\n",
+		"pkg/testdata/TestOrder/goldsert_jsonl.golden": "{\"id\":1}\n" +
+			"{\"id\":2}\n",
+		"testdata/TestBook/goldsert_xml.golden": canonical,
+	}
+	for name, content := range want {
+		data, err := ioutil.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		assert.Equal(t, content, string(data), name)
+	}
+
+	code, stdout, _ = runCLI("fmt", "-l", "testdata")
+	assert.Equal(t, 0, code)
+	assert.Empty(t, stdout)
+}
+
+func TestCLI_stats(t *testing.T) {
+	newTestRepo(t)
+
+	code, stdout, _ := runCLI("stats")
+
+	assert.Equal(t, 0, code)
+	assert.Equal(t, ""+
+		"PACKAGE  FORMAT  FILES  BYTES\n"+
+		".        dump    1      8\n"+
+		".        json    1      23\n"+
+		".        xml     1      44\n"+
+		".        yaml    1      20\n"+
+		".        (all)   4      95\n"+
+		"pkg      form    1      10\n"+
+		"pkg      json    1      7\n"+
+		"pkg      jsonl   1      19\n"+
+		"pkg      toml    1      7\n"+
+		"pkg      (all)   4      43\n"+
+		"(all)    (all)   8      138\n",
+		stdout,
+	)
+}
+
+// pendingRepo holds pending golden files, keyed by their path.
+var pendingRepo = map[string]string{
+	"testdata/TestBook/full/goldsert_json.golden.new": `{"id":"2"}`,
+	"testdata/TestBook/full/goldsert_yaml.golden.new": "id: \"2\"\n",
+	"testdata/TestBook/goldsert_form.golden.new":      "id=2\n",
+}
+
+func newPendingTestRepo(t *testing.T) string {
+	t.Helper()
+
+	dir := newTestRepo(t)
+	for name, content := range pendingRepo {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0o644))
+	}
+
+	return dir
+}
+
+func readTestFile(t *testing.T, path string) string {
+	t.Helper()
+
+	b, err := ioutil.ReadFile(filepath.FromSlash(path))
+	if os.IsNotExist(err) {
+		return "<missing>"
+	}
+	require.NoError(t, err)
+
+	return string(b)
+}
+
+func TestCLI_review(t *testing.T) {
+	newPendingTestRepo(t)
+
+	code, stdout, stderr := runCLIWithInput("x\na\nr\n", "review")
+	require.Equal(t, 0, code, stderr)
+
+	assert.Contains(t, stdout,
+		"--- testdata/TestBook/full/goldsert_json.golden\n"+
+			"+++ testdata/TestBook/full/goldsert_json.golden.new\n"+
+			"@@ -1 +1 @@\n"+
+			"-{\"id\":\"1\",\"tags\":[\"a\"]}\n"+
+			"+{\"id\":\"2\"}\n",
+	)
+	assert.Contains(t, stdout,
+		"--- /dev/null\n"+
+			"+++ testdata/TestBook/goldsert_form.golden.new\n",
+	)
+	assert.Contains(t, stdout,
+		"accepted: testdata/TestBook/full/goldsert_json.golden.new\n",
+	)
+	assert.Contains(t, stdout,
+		"rejected: testdata/TestBook/full/goldsert_yaml.golden.new\n",
+	)
+	assert.Contains(t, stdout,
+		"3 pending golden files, 1 accepted, 1 rejected, 1 skipped\n",
+	)
+
+	assert.Equal(t, `{"id":"2"}`,
+		readTestFile(t, "testdata/TestBook/full/goldsert_json.golden"),
+	)
+	assert.Equal(t, "<missing>",
+		readTestFile(t, "testdata/TestBook/full/goldsert_json.golden.new"),
+	)
+	assert.Equal(t, "id:   \"1\"\ntags: [a]\n",
+		readTestFile(t, "testdata/TestBook/full/goldsert_yaml.golden"),
+	)
+	assert.Equal(t, "<missing>",
+		readTestFile(t, "testdata/TestBook/full/goldsert_yaml.golden.new"),
+	)
+	assert.Equal(t, "<missing>",
+		readTestFile(t, "testdata/TestBook/goldsert_form.golden"),
+	)
+	assert.Equal(t, "id=2\n",
+		readTestFile(t, "testdata/TestBook/goldsert_form.golden.new"),
+	)
+}
+
+func TestCLI_review_flags(t *testing.T) {
+	newPendingTestRepo(t)
+
+	code, _, stderr := runCLI("review", "-accept", "-reject")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "mutually exclusive")
+
+	code, stdout, stderr := runCLI("review", "-reject", "-formats", "form")
+	require.Equal(t, 0, code, stderr)
+	assert.Equal(t,
+		"rejected: testdata/TestBook/goldsert_form.golden.new\n"+
+			"1 pending golden files, 0 accepted, 1 rejected, 0 skipped\n",
+		stdout,
+	)
+
+	code, stdout, stderr = runCLI("review", "-accept")
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stdout,
+		"2 pending golden files, 2 accepted, 0 rejected, 0 skipped\n",
+	)
+	assert.Equal(t, "id: \"2\"\n",
+		readTestFile(t, "testdata/TestBook/full/goldsert_yaml.golden"),
+	)
+
+	code, stdout, _ = runCLI("review")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "no pending golden files\n", stdout)
+}
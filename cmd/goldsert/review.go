@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// pendingSuffix is appended to golden file names by the review mode of
+// goldsert, for golden files awaiting review.
+const pendingSuffix = ".new"
+
+// Review decisions.
+const (
+	reviewAccept = "accept"
+	reviewReject = "reject"
+	reviewSkip   = "skip"
+	reviewQuit   = "quit"
+)
+
+// review shows the diff of each pending golden file against its golden file,
+// and accepts, rejects or skips it.
+func (c *cli) review(args []string) int {
+	var opts options
+	fs := c.flags("review", &opts)
+	accept := fs.Bool("accept", false, "accept all pending golden files")
+	reject := fs.Bool("reject", false, "reject all pending golden files")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+
+		return 2
+	}
+	if *accept && *reject {
+		fmt.Fprintln(c.stderr,
+			"goldsert: -accept and -reject are mutually exclusive",
+		)
+
+		return 2
+	}
+
+	opts.suffix += pendingSuffix
+	files, code, ok := c.find(fs.Args(), &opts)
+	if !ok {
+		return code
+	}
+	if len(files) == 0 {
+		fmt.Fprintln(c.stdout, "no pending golden files")
+
+		return 0
+	}
+
+	in := bufio.NewReader(c.stdin)
+	var accepted, rejected, skipped, failed int
+	for i, f := range files {
+		decision := reviewSkip
+		switch {
+		case *accept:
+			decision = reviewAccept
+		case *reject:
+			decision = reviewReject
+		default:
+			if err := c.diff(f.Path); err != nil {
+				fmt.Fprintf(c.stderr, "goldsert: %s\n", err)
+				failed++
+
+				continue
+			}
+			decision = c.prompt(in, i+1, len(files))
+		}
+
+		var err error
+		switch decision {
+		case reviewAccept:
+			err = os.Rename(f.Path, strings.TrimSuffix(f.Path, pendingSuffix))
+			if err == nil {
+				accepted++
+			}
+		case reviewReject:
+			err = os.Remove(f.Path)
+			if err == nil {
+				rejected++
+			}
+		case reviewSkip:
+			skipped++
+
+			continue
+		case reviewQuit:
+			skipped += len(files) - i
+		}
+		if decision == reviewQuit {
+			break
+		}
+
+		if err != nil {
+			fmt.Fprintf(c.stderr, "goldsert: %s\n", err)
+			failed++
+		} else {
+			fmt.Fprintf(c.stdout, "%sed: %s\n", decision, f.Path)
+		}
+	}
+
+	fmt.Fprintf(c.stdout,
+		"%d pending golden files, %d accepted, %d rejected, %d skipped\n",
+		len(files), accepted, rejected, skipped,
+	)
+	if failed > 0 {
+		return 1
+	}
+
+	return 0
+}
+
+// diff writes a unified diff of the golden file of the given pending golden
+// file against it. A missing golden file is treated as empty.
+func (c *cli) diff(pending string) error {
+	golden := strings.TrimSuffix(pending, pendingSuffix)
+
+	b, err := ioutil.ReadFile(pending)
+	if err != nil {
+		return err
+	}
+	a, err := ioutil.ReadFile(golden)
+	switch {
+	case os.IsNotExist(err):
+		golden = "/dev/null"
+	case err != nil:
+		return err
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(a)),
+		B:        difflib.SplitLines(string(b)),
+		FromFile: golden,
+		ToFile:   pending,
+		Context:  3,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(c.stdout, diff)
+	if !strings.HasSuffix(diff, "\n") {
+		fmt.Fprintln(c.stdout)
+	}
+
+	return nil
+}
+
+// prompt asks for the review decision of the n-th of total pending golden
+// files, until a valid answer is read. Failing to read, like at the end of
+// input, quits.
+func (c *cli) prompt(in *bufio.Reader, n, total int) string {
+	for {
+		fmt.Fprintf(c.stdout,
+			"[%d/%d] accept? [a]ccept, [r]eject, [s]kip, [q]uit: ", n, total,
+		)
+
+		line, err := in.ReadString('\n')
+		answer := strings.ToLower(strings.TrimSpace(line))
+		for _, d := range []string{
+			reviewAccept, reviewReject, reviewSkip, reviewQuit,
+		} {
+			if answer != "" && strings.HasPrefix(d, answer) {
+				return d
+			}
+		}
+		if err != nil {
+			fmt.Fprintln(c.stdout)
+
+			return reviewQuit
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"text/tabwriter"
+)
+
+// formatStats holds the count and total size of golden files.
+type formatStats struct {
+	files int
+	bytes int64
+}
+
+func (s *formatStats) add(f *goldenFile) {
+	s.files++
+	s.bytes += f.Size
+}
+
+// stats reports golden file counts and sizes per package and format.
+func (c *cli) stats(args []string) int {
+	var opts options
+	fs := c.flags("stats", &opts)
+	files, code, ok := c.parse(fs, args, &opts)
+	if !ok {
+		return code
+	}
+
+	var pkgs []string
+	totals := map[string]*formatStats{}
+	formats := map[string]map[string]*formatStats{}
+	var total formatStats
+	for _, f := range files {
+		if totals[f.Package] == nil {
+			pkgs = append(pkgs, f.Package)
+			totals[f.Package] = &formatStats{}
+			formats[f.Package] = map[string]*formatStats{}
+		}
+		if formats[f.Package][f.Format] == nil {
+			formats[f.Package][f.Format] = &formatStats{}
+		}
+
+		totals[f.Package].add(f)
+		formats[f.Package][f.Format].add(f)
+		total.add(f)
+	}
+	sort.Strings(pkgs)
+
+	w := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "PACKAGE\tFORMAT\tFILES\tBYTES")
+	for _, pkg := range pkgs {
+		names := make([]string, 0, len(formats[pkg]))
+		for name := range formats[pkg] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			s := formats[pkg][name]
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\n", pkg, name, s.files, s.bytes)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\n",
+			pkg, "(all)", totals[pkg].files, totals[pkg].bytes,
+		)
+	}
+	fmt.Fprintf(w, "%s\t%s\t%d\t%d\n",
+		"(all)", "(all)", total.files, total.bytes,
+	)
+	w.Flush()
+
+	return 0
+}
@@ -0,0 +1,425 @@
+package goldsert
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// NormalizedKey is a ConsistencyKeyFunc which lowercases keys and removes
+// underscores, hyphens and spaces, so keys like "foo_bar", "fooBar" and
+// "Foo-Bar" are all considered equal.
+func NormalizedKey(_ Format, key string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '_', '-', ' ':
+			return -1
+		}
+
+		return r
+	}, strings.ToLower(key))
+}
+
+// ExactKey is a ConsistencyKeyFunc which leaves keys as they are, so keys
+// must be identical across formats.
+func ExactKey(_ Format, key string) string {
+	return key
+}
+
+// CrossFormatConsistency asserts that the JSON, YAML and XML golden files of
+// the test carry the same data. Each golden file is decoded into a generic
+// tree, with keys mapped by ConsistencyKeyFunc, and all trees are compared,
+// reporting fields which are present in one format but missing in another,
+// and values which differ.
+//
+// Only the golden files of the given formats are compared. When no formats
+// are given, all JSON, YAML and XML golden files which exist for the test are
+// compared, of which there must be at least two.
+//
+// As formats differ in what they can represent, trees are compared loosely:
+// null values, empty strings, and empty lists and maps are considered
+// missing, scalars are compared by their string form, or numerically if they
+// are numbers, and a scalar is considered equal to a list holding only that
+// scalar, as XML does not distinguish them. The root element of XML
+// documents is ignored, and the attributes and child elements of each element
+// are compared with the keys of JSON and YAML documents. The text content of
+// elements with attributes or child elements is compared under a "#text" key.
+//
+// Golden files are never written, so this should be called after the
+// marshaling assertions which write them.
+func (s *Assert) CrossFormatConsistency(t *testing.T, formats ...Format) {
+	t.Helper()
+
+	if len(formats) == 0 {
+		for _, f := range []Format{JSON, YAML, XML} {
+			_, err := os.Stat(s.Golden.FileP(t, f.goldenName()))
+			if err == nil {
+				formats = append(formats, f)
+			}
+		}
+	}
+	require.GreaterOrEqualf(t, len(formats), 2,
+		"at least two golden files are required to compare, found: %v",
+		formats,
+	)
+
+	trees := make([]interface{}, len(formats))
+	for i, f := range formats {
+		s.touch(t, f.goldenName())
+		gold := s.normalize(s.Golden.GetP(t, f.goldenName()))
+
+		tree, err := s.consistencyTree(f, gold)
+		require.NoErrorf(t, err,
+			"failed to decode %s golden file %s",
+			f, s.Golden.FileP(t, f.goldenName()),
+		)
+		trees[i] = tree
+	}
+
+	diffs := diffTrees("", formats, trees)
+	if len(diffs) > 0 {
+		assert.Fail(t,
+			"golden files are inconsistent across formats",
+			strings.Join(diffs, "\n"),
+		)
+	}
+}
+
+// consistencyTree decodes data of the given format into a generic tree of
+// map[string]interface{}, []interface{} and string values.
+func (s *Assert) consistencyTree(
+	format Format,
+	data []byte,
+) (interface{}, error) {
+	keyFunc := s.ConsistencyKeyFunc
+	if keyFunc == nil {
+		keyFunc = NormalizedKey
+	}
+	key := func(k string) string { return keyFunc(format, k) }
+
+	var v interface{}
+	switch format {
+	case JSON:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&v); err != nil {
+			return nil, err
+		}
+	case YAML:
+		if err := yaml.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+	case XML:
+		return xmlTree(data, key)
+	default:
+		return nil, fmt.Errorf("%w: %q", errUnknownFormat, format)
+	}
+
+	return genericTree(v, key), nil
+}
+
+// genericTree converts a value decoded from JSON or YAML into a generic tree,
+// mapping keys with the given function, and removing null values, empty
+// strings, and empty lists and maps.
+func genericTree(v interface{}, key func(string) string) interface{} {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		m := map[string]interface{}{}
+		for k, e := range v {
+			if e = genericTree(e, key); e != nil {
+				m[key(k)] = e
+			}
+		}
+
+		return emptyTreeToNil(m)
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for k, e := range v {
+			if e = genericTree(e, key); e != nil {
+				m[key(fmt.Sprint(k))] = e
+			}
+		}
+
+		return emptyTreeToNil(m)
+	case []interface{}:
+		l := make([]interface{}, 0, len(v))
+		for _, e := range v {
+			l = append(l, genericTree(e, key))
+		}
+
+		return emptyTreeToNil(l)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case string:
+		return emptyTreeToNil(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func emptyTreeToNil(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+	case string:
+		if v == "" {
+			return nil
+		}
+	}
+
+	return v
+}
+
+// xmlTree decodes an XML document into a generic tree, mapping element and
+// attribute names with the given function.
+func xmlTree(data []byte, key func(string) string) (interface{}, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if start, ok := tok.(xml.StartElement); ok {
+			return xmlElementTree(dec, start, key)
+		}
+	}
+}
+
+func xmlElementTree(
+	dec *xml.Decoder,
+	start xml.StartElement,
+	key func(string) string,
+) (interface{}, error) {
+	m := map[string]interface{}{}
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+			continue
+		}
+		if attr.Value != "" {
+			m[key(attr.Name.Local)] = attr.Value
+		}
+	}
+
+	var text strings.Builder
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			child, err := xmlElementTree(dec, tok, key)
+			if err != nil {
+				return nil, err
+			}
+			if child == nil {
+				continue
+			}
+
+			k := key(tok.Name.Local)
+			switch existing := m[k].(type) {
+			case nil:
+				m[k] = child
+			case []interface{}:
+				m[k] = append(existing, child)
+			default:
+				m[k] = []interface{}{existing, child}
+			}
+		case xml.CharData:
+			text.Write(tok)
+		case xml.EndElement:
+			s := strings.TrimSpace(text.String())
+			if len(m) == 0 {
+				return emptyTreeToNil(s), nil
+			}
+			if s != "" {
+				m["#text"] = s
+			}
+
+			return m, nil
+		}
+	}
+}
+
+// diffTrees compares the trees of each format at the given path, returning a
+// description of each difference.
+func diffTrees(path string, formats []Format, trees []interface{}) []string {
+	var present, missing []string
+	var maps, lists int
+	for i, tree := range trees {
+		switch tree.(type) {
+		case nil:
+			missing = append(missing, string(formats[i]))
+
+			continue
+		case map[string]interface{}:
+			maps++
+		case []interface{}:
+			lists++
+		}
+		present = append(present, string(formats[i]))
+	}
+
+	switch {
+	case len(present) == 0:
+		return nil
+	case len(missing) > 0:
+		return []string{fmt.Sprintf("%s: present in %s, missing in %s",
+			treePath(path), strings.Join(present, ", "),
+			strings.Join(missing, ", "),
+		)}
+	case maps == len(trees):
+		return diffTreeMaps(path, formats, trees)
+	case maps == 0 && lists > 0:
+		return diffTreeLists(path, formats, trees)
+	case maps == 0:
+		return diffTreeScalars(path, formats, trees)
+	default:
+		return []string{fmt.Sprintf("%s: differs in kind, %s",
+			treePath(path), describeTrees(formats, trees),
+		)}
+	}
+}
+
+func diffTreeMaps(
+	path string,
+	formats []Format,
+	trees []interface{},
+) []string {
+	keySet := map[string]bool{}
+	for _, tree := range trees {
+		for k := range tree.(map[string]interface{}) {
+			keySet[k] = true
+		}
+	}
+	keys := make([]string, 0, len(keySet))
+	for k := range keySet {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var diffs []string
+	for _, k := range keys {
+		children := make([]interface{}, len(trees))
+		for i, tree := range trees {
+			children[i] = tree.(map[string]interface{})[k]
+		}
+		diffs = append(diffs, diffTrees(path+"."+k, formats, children)...)
+	}
+
+	return diffs
+}
+
+func diffTreeLists(
+	path string,
+	formats []Format,
+	trees []interface{},
+) []string {
+	lists := make([][]interface{}, len(trees))
+	size := 0
+	for i, tree := range trees {
+		l, ok := tree.([]interface{})
+		if !ok {
+			l = []interface{}{tree}
+		}
+		lists[i] = l
+		if len(l) > size {
+			size = len(l)
+		}
+	}
+
+	var diffs []string
+	for n := 0; n < size; n++ {
+		elems := make([]interface{}, len(trees))
+		for i, l := range lists {
+			if n < len(l) {
+				elems[i] = l[n]
+			}
+		}
+		diffs = append(diffs,
+			diffTrees(path+"["+strconv.Itoa(n)+"]", formats, elems)...,
+		)
+	}
+
+	return diffs
+}
+
+func diffTreeScalars(
+	path string,
+	formats []Format,
+	trees []interface{},
+) []string {
+	for _, tree := range trees[1:] {
+		if !scalarsEqual(trees[0].(string), tree.(string)) {
+			return []string{fmt.Sprintf("%s: differs in value, %s",
+				treePath(path), describeTrees(formats, trees),
+			)}
+		}
+	}
+
+	return nil
+}
+
+// scalarsEqual returns true if a and b are identical, or are equal numbers.
+func scalarsEqual(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	fa, errA := strconv.ParseFloat(a, 64)
+	fb, errB := strconv.ParseFloat(b, 64)
+
+	return errA == nil && errB == nil && fa == fb
+}
+
+func describeTrees(formats []Format, trees []interface{}) string {
+	parts := make([]string, len(trees))
+	for i, tree := range trees {
+		var desc string
+		switch tree := tree.(type) {
+		case map[string]interface{}:
+			desc = "map"
+		case []interface{}:
+			desc = "list"
+		default:
+			desc = strconv.Quote(fmt.Sprint(tree))
+		}
+		parts[i] = string(formats[i]) + "=" + desc
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func treePath(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+
+	return path
+}
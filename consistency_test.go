@@ -0,0 +1,152 @@
+package goldsert
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Bookcase struct {
+	Name     string   `json:"name" yaml:"name" xml:"name,attr"`
+	Books    []*Book  `json:"books" yaml:"books" xml:"books"`
+	Tags     []string `json:"tags,omitempty" yaml:"tags,omitempty" xml:"tags"`
+	Capacity int      `json:"capacity" yaml:"capacity" xml:"capacity"`
+}
+
+var crossFormatConsistencyTestCases = []struct {
+	name string
+	v    interface{}
+}{
+	{name: "empty book", v: &Book{}},
+	{
+		name: "full book",
+		v: &Book{
+			ID:     "cfda163c-d5c1-44a2-909b-5d2ce3a31979",
+			Title:  "The Traveler",
+			Author: &Author{FirstName: "John", LastName: "Twelve Hawks"},
+			Year:   2005,
+		},
+	},
+	{
+		name: "article with date",
+		v: &Article{
+			ID:     "10eec54d-e30a-4428-be18-01095d889126",
+			Title:  "Time Travel",
+			Author: &Author{FirstName: "Doc", LastName: "Brown"},
+			Date:   &articleDate,
+		},
+	},
+	{
+		name: "bookcase",
+		v: &Bookcase{
+			Name: "Fiction",
+			Books: []*Book{
+				{ID: "1", Title: "The Traveler", Year: 2005},
+				{ID: "2", Title: "The Dark River", Year: 2007},
+			},
+			Tags:     []string{"novels"},
+			Capacity: 42,
+		},
+	},
+}
+
+func TestAssert_CrossFormatConsistency(t *testing.T) {
+	for _, tt := range crossFormatConsistencyTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+
+			gs.JSONMarshaling(t, tt.v)
+			gs.YAMLMarshaling(t, tt.v)
+			gs.XMLMarshaling(t, tt.v)
+
+			gs.CrossFormatConsistency(t)
+			gs.CrossFormatConsistency(t, JSON, YAML)
+		})
+	}
+}
+
+func Test_diffTrees(t *testing.T) {
+	tests := []struct {
+		name    string
+		keyFunc func(Format, string) string
+		json    string
+		yaml    string
+		xml     string
+		want    []string
+	}{
+		{
+			name: "equal",
+			json: `{"foo_bar":"Hello","n":1.0,"tags":["a"],"nil":null}`,
+			yaml: "fooBar: Hello\nn: 1\ntags: a\nempty: []\n",
+			xml:  "<x><Foo_Bar>Hello</Foo_Bar><n>1</n><tags>a</tags></x>",
+		},
+		{
+			name:    "exact keys",
+			keyFunc: ExactKey,
+			json:    `{"foo_bar":"Hello"}`,
+			yaml:    "fooBar: Hello\n",
+			xml:     "<x><Foo_Bar>Hello</Foo_Bar></x>",
+			want: []string{
+				".Foo_Bar: present in xml, missing in json, yaml",
+				".fooBar: present in yaml, missing in json, xml",
+				".foo_bar: present in json, missing in yaml, xml",
+			},
+		},
+		{
+			name: "missing and differing values",
+			json: `{"id":"1","year":2005,"author":{"name":"John"}}`,
+			yaml: "id: \"1\"\nyear: 2006\n",
+			xml: "<x id=\"1\"><year>2005</year>" +
+				"<author><name>Jane</name></author></x>",
+			want: []string{
+				".author: present in json, xml, missing in yaml",
+				".year: differs in value, json=\"2005\" yaml=\"2006\" " +
+					"xml=\"2005\"",
+			},
+		},
+		{
+			name: "lists",
+			json: `{"tags":["a","b"],"ids":[1,2]}`,
+			yaml: "tags: [a]\nids: {first: 1}\n",
+			xml:  "<x><tags>a</tags><tags>c</tags><ids>1</ids><ids>2</ids></x>",
+			want: []string{
+				".ids: differs in kind, json=list yaml=map xml=list",
+				".tags[1]: present in json, xml, missing in yaml",
+			},
+		},
+		{
+			name: "xml text and attributes",
+			json: `{"id":"1","#text":"Hello"}`,
+			yaml: "id: \"1\"\n\"#text\": Hello\n",
+			xml:  "<x id=\"1\">Hello</x>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+			if tt.keyFunc != nil {
+				gs.ConsistencyKeyFunc = tt.keyFunc
+			}
+
+			formats := []Format{JSON, YAML, XML}
+			docs := []string{tt.json, tt.yaml, tt.xml}
+			trees := make([]interface{}, len(formats))
+			for i, f := range formats {
+				tree, err := gs.consistencyTree(f, []byte(docs[i]))
+				require.NoError(t, err)
+				trees[i] = tree
+			}
+
+			got := diffTrees("", formats, trees)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNormalizedKey(t *testing.T) {
+	for _, key := range []string{"foo_bar", "fooBar", "Foo-Bar", "FOO BAR"} {
+		assert.Equal(t, "foobar", NormalizedKey(JSON, key))
+	}
+}
@@ -0,0 +1,366 @@
+package goldsert
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Dump asserts that the given "v" value renders to an expected Go-syntax
+// representation fetched from a golden file on disk. It is intended for types
+// which are not serialized in any other way, and there is no unmarshaling
+// stage.
+//
+// Rendering is deterministic; map entries are sorted by key, and memory
+// addresses are never rendered. Pointers and maps which have already been
+// rendered are replaced by a reference to the path where they were first
+// rendered, like <same as v.Books[0].Author>, which also guards against
+// infinite recursion on cyclic values. Values of time.Time are rendered as
+// calls to time.Date.
+//
+// Unexported struct fields are only rendered when DumpUnexported is enabled.
+// Their values are always rendered structurally, including values of
+// time.Time, as methods cannot be called on them.
+func (s *Assert) Dump(t *testing.T, v interface{}) {
+	t.Helper()
+
+	dumped := s.normalize(dump(v, s.DumpUnexported))
+	gold := s.GoldenP(t, "goldsert_dump", dumped, nil)
+	assert.Equal(t, string(gold), string(dumped))
+}
+
+// dump renders v as Go-syntax followed by a newline.
+func dump(v interface{}, unexported bool) []byte {
+	d := &dumper{unexported: unexported, visited: map[dumpRef]string{}}
+	d.value(reflect.ValueOf(v), "v", 0, true)
+	d.buf.WriteString("\n")
+
+	return d.buf.Bytes()
+}
+
+// dumpRef identifies a pointer, map or slice value which has been rendered.
+// Slices are identified by their length too, as slices of different lengths
+// may share the same data pointer.
+type dumpRef struct {
+	typ reflect.Type
+	ptr uintptr
+	len int
+}
+
+// dumper renders values as Go-syntax.
+type dumper struct {
+	buf        bytes.Buffer
+	unexported bool
+
+	// visited holds the path of each pointer, map and slice value which has
+	// been rendered.
+	visited map[dumpRef]string
+}
+
+// value renders rv at the given path and indentation level. When typed is
+// true, rv is held in an interface and scalars not of a default literal type
+// (bool, int, float64 or string) are wrapped in a type conversion.
+func (d *dumper) value(rv reflect.Value, path string, indent int, typed bool) {
+	if !rv.IsValid() {
+		d.buf.WriteString("nil")
+
+		return
+	}
+
+	if rv.Type() == timeType && rv.CanInterface() {
+		d.time(rv.Interface().(time.Time))
+
+		return
+	}
+
+	switch rv.Kind() {
+	case reflect.Interface:
+		if rv.IsNil() {
+			d.buf.WriteString("nil")
+
+			return
+		}
+		d.value(rv.Elem(), path, indent, true)
+	case reflect.Ptr:
+		d.pointer(rv, path, indent)
+	case reflect.Struct:
+		d.structValue(rv, path, indent)
+	case reflect.Map:
+		d.mapValue(rv, path, indent)
+	case reflect.Slice:
+		if rv.IsNil() {
+			d.buf.WriteString(rv.Type().String() + "(nil)")
+
+			return
+		}
+		// Empty slices may all share the same data pointer, and cannot hold
+		// a cycle.
+		if rv.Len() > 0 && d.seen(rv, path) {
+			return
+		}
+		d.list(rv, path, indent)
+	case reflect.Array:
+		d.list(rv, path, indent)
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		if rv.IsNil() {
+			d.buf.WriteString("(" + rv.Type().String() + ")(nil)")
+
+			return
+		}
+		d.buf.WriteString("(" + rv.Type().String() + ")(<non-nil>)")
+	default:
+		d.scalar(rv, typed)
+	}
+}
+
+func (d *dumper) pointer(rv reflect.Value, path string, indent int) {
+	if rv.IsNil() {
+		d.buf.WriteString("(" + rv.Type().String() + ")(nil)")
+
+		return
+	}
+
+	if d.seen(rv, path) {
+		return
+	}
+
+	elem := rv.Elem()
+	switch elem.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		d.buf.WriteString("&")
+		d.value(elem, path, indent, false)
+	default:
+		d.buf.WriteString("&" + elem.Type().String() + "(")
+		d.value(elem, path, indent, false)
+		d.buf.WriteString(")")
+	}
+}
+
+func (d *dumper) structValue(rv reflect.Value, path string, indent int) {
+	typ := rv.Type()
+	d.buf.WriteString(typ.String() + "{")
+
+	fields := 0
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" && !d.unexported {
+			continue
+		}
+
+		typed := f.Type.Kind() == reflect.Interface
+		d.newline(indent + 1)
+		d.buf.WriteString(f.Name + ": ")
+		d.value(rv.Field(i), path+"."+f.Name, indent+1, typed)
+		d.buf.WriteString(",")
+		fields++
+	}
+
+	if fields > 0 {
+		d.newline(indent)
+	}
+	d.buf.WriteString("}")
+}
+
+func (d *dumper) mapValue(rv reflect.Value, path string, indent int) {
+	typ := rv.Type()
+	if rv.IsNil() {
+		d.buf.WriteString(typ.String() + "(nil)")
+
+		return
+	}
+
+	if d.seen(rv, path) {
+		return
+	}
+
+	keyTyped := typ.Key().Kind() == reflect.Interface
+	elemTyped := typ.Elem().Kind() == reflect.Interface
+
+	keys := rv.MapKeys()
+	rendered := make([]string, len(keys))
+	for i, key := range keys {
+		kd := &dumper{unexported: d.unexported, visited: map[dumpRef]string{}}
+		kd.value(key, "", 0, keyTyped)
+		rendered[i] = kd.buf.String()
+	}
+	sort.Sort(&dumpMapKeys{keys: keys, rendered: rendered})
+
+	d.buf.WriteString(typ.String() + "{")
+	for i, key := range keys {
+		d.newline(indent + 1)
+		d.buf.WriteString(rendered[i] + ": ")
+		d.value(
+			rv.MapIndex(key), path+"["+rendered[i]+"]", indent+1, elemTyped,
+		)
+		d.buf.WriteString(",")
+	}
+	if len(keys) > 0 {
+		d.newline(indent)
+	}
+	d.buf.WriteString("}")
+}
+
+func (d *dumper) list(rv reflect.Value, path string, indent int) {
+	d.buf.WriteString(rv.Type().String() + "{")
+
+	if rv.Type().Elem().Kind() == reflect.Uint8 {
+		d.bytes(rv, indent)
+		d.buf.WriteString("}")
+
+		return
+	}
+
+	elemTyped := rv.Type().Elem().Kind() == reflect.Interface
+	for i := 0; i < rv.Len(); i++ {
+		d.newline(indent + 1)
+		d.value(
+			rv.Index(i), path+"["+strconv.Itoa(i)+"]", indent+1, elemTyped,
+		)
+		d.buf.WriteString(",")
+	}
+	if rv.Len() > 0 {
+		d.newline(indent)
+	}
+	d.buf.WriteString("}")
+}
+
+// bytes renders the elements of a byte slice or array as hex, 8 per line.
+func (d *dumper) bytes(rv reflect.Value, indent int) {
+	for i := 0; i < rv.Len(); i++ {
+		if i%8 == 0 {
+			d.newline(indent + 1)
+		} else {
+			d.buf.WriteString(" ")
+		}
+		fmt.Fprintf(&d.buf, "0x%02x,", rv.Index(i).Uint())
+	}
+	if rv.Len() > 0 {
+		d.newline(indent)
+	}
+}
+
+func (d *dumper) scalar(rv reflect.Value, typed bool) {
+	var s string
+	var defaultType reflect.Kind
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		s, defaultType = strconv.FormatBool(rv.Bool()), reflect.Bool
+	case reflect.String:
+		s, defaultType = strconv.Quote(rv.String()), reflect.String
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		s, defaultType = strconv.FormatInt(rv.Int(), 10), reflect.Int
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		s = strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		s, defaultType = formatDumpFloat(rv.Float(), rv.Type().Bits()),
+			reflect.Float64
+	case reflect.Complex64, reflect.Complex128:
+		c := rv.Complex()
+		s = "complex(" + formatDumpFloat(real(c), rv.Type().Bits()/2) +
+			", " + formatDumpFloat(imag(c), rv.Type().Bits()/2) + ")"
+		defaultType = reflect.Complex128
+	default:
+		s = "<" + rv.Type().String() + ">"
+	}
+
+	typ := rv.Type()
+	if typed && (typ.PkgPath() != "" || typ.Kind() != defaultType) {
+		s = typ.String() + "(" + s + ")"
+	}
+
+	d.buf.WriteString(s)
+}
+
+func (d *dumper) time(tm time.Time) {
+	loc := "time.Location(" + strconv.Quote(tm.Location().String()) + ")"
+	switch tm.Location() {
+	case time.UTC:
+		loc = "time.UTC"
+	case time.Local:
+		loc = "time.Local"
+	}
+
+	fmt.Fprintf(&d.buf, "time.Date(%d, time.%s, %d, %d, %d, %d, %d, %s)",
+		tm.Year(), tm.Month(), tm.Day(),
+		tm.Hour(), tm.Minute(), tm.Second(), tm.Nanosecond(), loc,
+	)
+}
+
+// seen records the given pointer, map or slice as rendered at path, returning
+// true and rendering a reference to the path where it was first rendered if it
+// had already been recorded.
+func (d *dumper) seen(rv reflect.Value, path string) bool {
+	ref := dumpRef{typ: rv.Type(), ptr: rv.Pointer()}
+	if rv.Kind() == reflect.Slice {
+		ref.len = rv.Len()
+	}
+	if first, ok := d.visited[ref]; ok {
+		d.buf.WriteString("<same as " + first + ">")
+
+		return true
+	}
+	d.visited[ref] = path
+
+	return false
+}
+
+func (d *dumper) newline(indent int) {
+	d.buf.WriteString("\n" + strings.Repeat("\t", indent))
+}
+
+func formatDumpFloat(f float64, bits int) string {
+	s := strconv.FormatFloat(f, 'g', -1, bits)
+	if !strings.ContainsAny(s, ".eEnN") {
+		s += ".0"
+	}
+
+	return s
+}
+
+// dumpMapKeys sorts map keys by value when they are of a basic numeric kind,
+// and by their rendered representation otherwise.
+type dumpMapKeys struct {
+	keys     []reflect.Value
+	rendered []string
+}
+
+func (s *dumpMapKeys) Len() int {
+	return len(s.keys)
+}
+
+func (s *dumpMapKeys) Swap(i, j int) {
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+	s.rendered[i], s.rendered[j] = s.rendered[j], s.rendered[i]
+}
+
+func (s *dumpMapKeys) Less(i, j int) bool {
+	a, b := s.keys[i], s.keys[j]
+	if a.Kind() == b.Kind() {
+		switch a.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+			reflect.Int64:
+			return a.Int() < b.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+			reflect.Uint64, reflect.Uintptr:
+			return a.Uint() < b.Uint()
+		case reflect.Float32, reflect.Float64:
+			return a.Float() < b.Float()
+		case reflect.String:
+			return a.String() < b.String()
+		}
+	}
+
+	return s.rendered[i] < s.rendered[j]
+}
@@ -0,0 +1,128 @@
+package goldsert
+
+import (
+	"testing"
+	"time"
+)
+
+type Priority int
+
+type Task struct {
+	ID       int
+	Title    string
+	Priority Priority
+	Done     bool
+	Due      *time.Time
+	Owner    *Author
+	Reviewer *Author
+	Tags     []string
+	Estimate map[string]float64
+	Checksum []byte
+	Extra    interface{}
+	Parent   *Task
+	Subtasks []*Task
+	OnDone   func()
+
+	attempts int
+	lastRun  time.Time
+}
+
+func newTaskTree() *Task {
+	owner := &Author{FirstName: "John", LastName: "Twelve Hawks"}
+	due := time.Date(2021, time.October, 27, 22, 30, 34, 0, time.UTC)
+
+	root := &Task{
+		ID:       1,
+		Title:    "Write \"golden\" tests",
+		Priority: 2,
+		Due:      &due,
+		Owner:    owner,
+		Reviewer: owner,
+		Tags:     []string{"testing", "go"},
+		Estimate: map[string]float64{"hours": 4, "days": 0.5},
+		Checksum: []byte{
+			0xde, 0xad, 0xbe, 0xef, 0x00, 0x01, 0x02, 0x03,
+			0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b,
+			0x0c,
+		},
+		Extra:    []interface{}{1, "two", 3.0, uint8(4), Priority(5), nil},
+		OnDone:   func() {},
+		attempts: 3,
+		lastRun:  due,
+	}
+	root.Subtasks = []*Task{
+		{ID: 2, Title: "Marshal", Parent: root, Done: true},
+		{ID: 3, Title: "Unmarshal", Parent: root, Owner: owner},
+	}
+
+	return root
+}
+
+func newCyclicSlice() []interface{} {
+	s := []interface{}{"a", nil}
+	s[1] = s
+
+	return s
+}
+
+var dumpTestCases = []struct {
+	name string
+	v    interface{}
+}{
+	{name: "nil", v: nil},
+	{name: "bool", v: true},
+	{name: "int", v: 42},
+	{name: "int8", v: int8(-8)},
+	{name: "uint", v: uint(42)},
+	{name: "float64", v: 3.0},
+	{name: "float32", v: float32(1.5)},
+	{name: "complex", v: complex(1, -2)},
+	{name: "string", v: "hello\tworld"},
+	{name: "named int", v: Priority(3)},
+	{name: "int pointer", v: intPtr(42)},
+	{name: "nil pointer", v: (*Task)(nil)},
+	{name: "nil slice", v: []string(nil)},
+	{name: "empty slice", v: []string{}},
+	{name: "nil map", v: map[string]int(nil)},
+	{
+		name: "int keyed map",
+		v:    map[int]string{10: "ten", 2: "two", -1: "minus one"},
+	},
+	{
+		name: "interface keyed map",
+		v: map[interface{}]bool{
+			"b": true, 1: false, "a": true, Priority(1): true,
+		},
+	},
+	{
+		name: "time",
+		v:    time.Date(2021, time.October, 27, 22, 30, 34, 0, time.UTC),
+	},
+	{
+		name: "time in fixed zone",
+		v: time.Date(
+			2021, time.October, 27, 22, 30, 34, 123,
+			time.FixedZone("CEST", 2*60*60),
+		),
+	},
+	{name: "empty struct", v: &Task{}},
+	{name: "struct tree", v: newTaskTree()},
+	{name: "cyclic slice", v: newCyclicSlice()},
+}
+
+func TestAssert_Dump(t *testing.T) {
+	for _, tt := range dumpTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+
+			gs.Dump(t, tt.v)
+		})
+	}
+}
+
+func TestAssert_Dump_DumpUnexported(t *testing.T) {
+	gs := New()
+	gs.DumpUnexported = true
+
+	gs.Dump(t, newTaskTree())
+}
@@ -0,0 +1,73 @@
+package goldsert
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+// The following functions report if the content of a golden file and
+// marshaled data are equal, under the same comparison as the assertions they
+// belong to apply. They are used to decide if golden files need to be
+// written.
+
+func jsonEqual(gold, data []byte) bool {
+	var a, b interface{}
+	if json.Unmarshal(gold, &a) != nil || json.Unmarshal(data, &b) != nil {
+		return false
+	}
+
+	return assert.ObjectsAreEqual(a, b)
+}
+
+func yamlEqual(gold, data []byte) bool {
+	var a, b interface{}
+	if yaml.Unmarshal(gold, &a) != nil || yaml.Unmarshal(data, &b) != nil {
+		return false
+	}
+
+	return assert.ObjectsAreEqual(a, b)
+}
+
+func jsonLinesEqual(gold, data []byte) bool {
+	goldLines := splitLines(gold)
+	lines := splitLines(data)
+	if len(goldLines) != len(lines) {
+		return false
+	}
+	for i := range lines {
+		if !jsonEqual(goldLines[i], lines[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func yamlDocumentsEqual(gold, data []byte) bool {
+	goldDocs, err := decodeYAMLDocuments(gold)
+	if err != nil {
+		return false
+	}
+	docs, err := decodeYAMLDocuments(data)
+	if err != nil {
+		return false
+	}
+
+	return assert.ObjectsAreEqual(goldDocs, docs)
+}
+
+func formEqual(gold, data []byte) bool {
+	goldValues, err := decodeFormGolden(gold)
+	if err != nil {
+		return false
+	}
+	values, err := decodeFormGolden(data)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(encodeFormGolden(goldValues), encodeFormGolden(values))
+}
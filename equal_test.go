@@ -0,0 +1,102 @@
+package goldsert
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_goldenEqualFuncs(t *testing.T) {
+	tests := []struct {
+		name  string
+		equal func(gold, data []byte) bool
+		gold  string
+		data  string
+		want  bool
+	}{
+		{
+			name:  "json whitespace",
+			equal: jsonEqual,
+			gold:  "{\n  \"id\": 1,\n  \"tags\": [\"a\"]\n}\n",
+			data:  "{\"tags\":[\"a\"],\"id\":1}",
+			want:  true,
+		},
+		{
+			name:  "json value",
+			equal: jsonEqual,
+			gold:  "{\"id\":1}",
+			data:  "{\"id\":2}",
+			want:  false,
+		},
+		{
+			name:  "json invalid",
+			equal: jsonEqual,
+			gold:  "{\"id\":",
+			data:  "{\"id\":1}",
+			want:  false,
+		},
+		{
+			name:  "yaml style",
+			equal: yamlEqual,
+			gold:  "id: 1\ntags:\n  - a\n",
+			data:  "tags: [a]\nid: 1\n",
+			want:  true,
+		},
+		{
+			name:  "yaml value",
+			equal: yamlEqual,
+			gold:  "id: 1\n",
+			data:  "id: \"1\"\n",
+			want:  false,
+		},
+		{
+			name:  "json lines",
+			equal: jsonLinesEqual,
+			gold:  "{\"id\":1}\n{\"id\":2}\n",
+			data:  "{\"id\": 1}\n{\"id\": 2}\n",
+			want:  true,
+		},
+		{
+			name:  "json lines count",
+			equal: jsonLinesEqual,
+			gold:  "{\"id\":1}\n",
+			data:  "{\"id\":1}\n{\"id\":2}\n",
+			want:  false,
+		},
+		{
+			name:  "yaml documents",
+			equal: yamlDocumentsEqual,
+			gold:  "id: 1\n---\nid: 2\n",
+			data:  "{id: 1}\n---\n{id: 2}\n",
+			want:  true,
+		},
+		{
+			name:  "yaml documents order",
+			equal: yamlDocumentsEqual,
+			gold:  "id: 1\n---\nid: 2\n",
+			data:  "id: 2\n---\nid: 1\n",
+			want:  false,
+		},
+		{
+			name:  "form order",
+			equal: formEqual,
+			gold:  "id=1\nname=a\n",
+			data:  "name=a\nid=1\n",
+			want:  true,
+		},
+		{
+			name:  "form value",
+			equal: formEqual,
+			gold:  "id=1\n",
+			data:  "id=2\n",
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.equal([]byte(tt.gold), []byte(tt.data))
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package goldsert
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// FormMarshaling asserts that the given "v" value encodes to expected
+// application/x-www-form-urlencoded values fetched from a golden file on disk,
+// and then verifies that decoding the golden file produces a value that is
+// equal to "v".
+//
+// Values are encoded using "form" struct tags, falling back to "url" struct
+// tags. The golden file holds one query escaped "key=value" pair per line,
+// sorted by key.
+//
+// Used for objects that do NOT change when they are encoded and decoded.
+func (s *Assert) FormMarshaling(t *testing.T, v interface{}) {
+	t.Helper()
+
+	s.FormMarshalingP(t, v, v)
+}
+
+// FormMarshalingP asserts that the given "v" value encodes to expected
+// application/x-www-form-urlencoded values fetched from a golden file on disk,
+// and then verifies that decoding the golden file produces a value that is
+// equal to "want".
+//
+// Values are encoded using "form" struct tags, falling back to "url" struct
+// tags. The golden file holds one query escaped "key=value" pair per line,
+// sorted by key.
+//
+// Used for objects that change when they are encoded and decoded.
+func (s *Assert) FormMarshalingP(t *testing.T, v, want interface{}) {
+	t.Helper()
+
+	codec := &formCodec{keyFunc: s.FormKeyFunc}
+
+	values, err := codec.encode(v)
+	require.NoErrorf(t, err, "failed to form encode %T: %+v", v, v)
+
+	gold := s.GoldenP(t,
+		"goldsert_form", encodeFormGolden(values), formEqual,
+	)
+	goldValues, err := decodeFormGolden(gold)
+	require.NoErrorf(t, err,
+		"failed to parse form values from %s",
+		s.Golden.FileP(t, "goldsert_form"),
+	)
+	assert.Equal(t,
+		string(encodeFormGolden(goldValues)), string(encodeFormGolden(values)),
+	)
+
+	requirePtr(t, want)
+
+	got := reflect.New(reflect.TypeOf(want).Elem()).Interface()
+	err = codec.decode(goldValues, got)
+	require.NoErrorf(t, err,
+		"failed to form decode %T from %s",
+		got, s.Golden.FileP(t, "goldsert_form"),
+	)
+	assert.Equal(t, want, got,
+		"unmarshaling from golden file does not match expected object",
+	)
+}
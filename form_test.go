@@ -0,0 +1,320 @@
+package goldsert
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SearchQuery struct {
+	Query    string            `form:"q"`
+	Page     int               `form:"page,omitempty"`
+	PerPage  *uint16           `url:"per_page,omitempty"`
+	Exact    bool              `form:"exact,omitempty"`
+	Score    float64           `form:"score,omitempty"`
+	Tags     []string          `form:"tag,omitempty"`
+	Since    *time.Time        `form:"since,omitempty"`
+	Filter   *SearchFilter     `form:"filter,omitempty"`
+	Sort     []SearchSort      `form:"sort,omitempty"`
+	Labels   map[string]string `form:"labels,omitempty"`
+	Internal string            `form:"-"`
+	Fallback string
+	token    string
+
+	SearchPaging
+}
+
+type SearchFilter struct {
+	Author string   `form:"author,omitempty"`
+	Years  [2]int   `form:"years"`
+	Langs  []string `form:"lang,omitempty"`
+}
+
+type SearchSort struct {
+	Field string `form:"field"`
+	Desc  bool   `form:"desc,omitempty"`
+}
+
+type SearchPaging struct {
+	Cursor string `form:"cursor,omitempty"`
+}
+
+func uint16Ptr(u uint16) *uint16 {
+	return &u
+}
+
+var searchSince = time.Date(2021, time.October, 27, 22, 30, 34, 0, time.UTC)
+
+var formTestCases = []struct {
+	name string
+	v    interface{}
+}{
+	{
+		name: "empty struct",
+		v:    &SearchQuery{},
+	},
+	{
+		name: "full struct",
+		v: &SearchQuery{
+			Query:   "golden files & more",
+			Page:    2,
+			PerPage: uint16Ptr(50),
+			Exact:   true,
+			Score:   0.75,
+			Tags:    []string{"go", "testing"},
+			Since:   &searchSince,
+			Filter: &SearchFilter{
+				Author: "Jane Doe",
+				Years:  [2]int{2005, 2021},
+				Langs:  []string{"en", "sv"},
+			},
+			Sort: []SearchSort{
+				{Field: "year", Desc: true},
+				{Field: "title"},
+			},
+			Labels:       map[string]string{"team": "core", "env": "ci"},
+			Fallback:     "used",
+			SearchPaging: SearchPaging{Cursor: "abc123"},
+		},
+	},
+	{
+		name: "url.Values",
+		v: &url.Values{
+			"q":   {"hello world"},
+			"tag": {"b", "a"},
+		},
+	},
+	{
+		name: "map of strings",
+		v:    &map[string]string{"name": "Jane", "city": "Stockholm"},
+	},
+}
+
+var formPTestCases = []struct {
+	name string
+	v    interface{}
+	want interface{}
+}{
+	{
+		name: "empty struct",
+		v:    &SearchQuery{},
+		want: &SearchQuery{},
+	},
+	{
+		name: "ignored fields",
+		v: &SearchQuery{
+			Query:    "golden",
+			Internal: "secret",
+			token:    "2fd5af35",
+		},
+		want: &SearchQuery{Query: "golden"},
+	},
+	{
+		name: "empty slices and maps",
+		v: &SearchQuery{
+			Query:  "golden",
+			Tags:   []string{},
+			Sort:   []SearchSort{},
+			Labels: map[string]string{},
+			Filter: &SearchFilter{},
+		},
+		want: &SearchQuery{
+			Query:  "golden",
+			Filter: &SearchFilter{},
+		},
+	},
+}
+
+func TestAssert_FormMarshaling(t *testing.T) {
+	for _, tt := range formTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+
+			gs.FormMarshaling(t, tt.v)
+		})
+	}
+}
+
+func TestAssert_FormMarshalingP(t *testing.T) {
+	for _, tt := range formPTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+
+			gs.FormMarshalingP(t, tt.v, tt.want)
+		})
+	}
+}
+
+func TestAssert_FormMarshaling_BracketFormKey(t *testing.T) {
+	for _, tt := range formTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+			gs.FormKeyFunc = BracketFormKey
+
+			gs.FormMarshaling(t, tt.v)
+		})
+	}
+}
+
+func Test_formCodec_encode(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       interface{}
+		want    url.Values
+		wantErr string
+	}{
+		{
+			name: "struct",
+			v: &SearchQuery{
+				Query:  "golden",
+				Filter: &SearchFilter{Years: [2]int{2005, 2021}},
+				Sort:   []SearchSort{{Field: "year", Desc: true}},
+			},
+			want: url.Values{
+				"q":            {"golden"},
+				"filter.years": {"2005", "2021"},
+				"sort.0.field": {"year"},
+				"sort.0.desc":  {"true"},
+				"Fallback":     {""},
+			},
+		},
+		{
+			name:    "scalar",
+			v:       intPtr(42),
+			wantErr: "unsupported form type: *int is not a struct or map",
+		},
+		{
+			name: "nil pointer",
+			v:    (*SearchQuery)(nil),
+			wantErr: "unsupported form type: *goldsert.SearchQuery is not " +
+				"a struct or map",
+		},
+		{
+			name: "time.Time",
+			v:    &searchSince,
+			wantErr: "unsupported form type: *time.Time is not a struct " +
+				"or map",
+		},
+		{
+			name: "non-string map keys",
+			v:    map[int]string{1: "one"},
+			wantErr: "unsupported form type: map[int]string has " +
+				"non-string keys",
+		},
+		{
+			name: "map of structs",
+			v: map[string]SearchSort{
+				"a": {Field: "name"},
+			},
+			wantErr: "unsupported form type: map[string]goldsert.SearchSort " +
+				"has values which are not scalars or slices of scalars",
+		},
+		{
+			name: "nested maps",
+			v: &struct {
+				M map[string]map[string]int `form:"m"`
+			}{M: map[string]map[string]int{"a": {"b": 1}}},
+			wantErr: "unsupported form type: map[string]map[string]int " +
+				"has values which are not scalars or slices of scalars",
+		},
+		{
+			name:    "unsupported field type",
+			v:       &struct{ C chan int }{C: make(chan int)},
+			wantErr: `unsupported form type: chan int at key "C"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &formCodec{keyFunc: DottedFormKey}
+
+			got, err := c.encode(tt.v)
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_formCodec_decode(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  url.Values
+		v       interface{}
+		want    interface{}
+		wantErr string
+	}{
+		{
+			name: "struct",
+			values: url.Values{
+				"q":            {"golden"},
+				"filter.years": {"2005", "2021"},
+				"sort.0.field": {"year"},
+				"sort.1.field": {"title"},
+				"labels.env":   {"ci"},
+			},
+			v: &SearchQuery{},
+			want: &SearchQuery{
+				Query:  "golden",
+				Filter: &SearchFilter{Years: [2]int{2005, 2021}},
+				Sort:   []SearchSort{{Field: "year"}, {Field: "title"}},
+				Labels: map[string]string{"env": "ci"},
+			},
+		},
+		{
+			name: "unknown keys",
+			values: url.Values{
+				"q":            {"golden"},
+				"query":        {"golden"},
+				"filter.title": {"The Traveler"},
+			},
+			v:       &SearchQuery{},
+			wantErr: "unknown form keys: filter.title, query",
+		},
+		{
+			name:    "multiple values",
+			values:  url.Values{"q": {"golden", "files"}},
+			v:       &SearchQuery{},
+			wantErr: `multiple form values: "q"`,
+		},
+		{
+			name:    "too many array values",
+			values:  url.Values{"filter.years": {"1", "2", "3"}},
+			v:       &SearchQuery{},
+			wantErr: `multiple form values: "filter.years"`,
+		},
+		{
+			name:    "invalid value",
+			values:  url.Values{"page": {"two"}},
+			v:       &SearchQuery{},
+			wantErr: `strconv.ParseInt: parsing "two": invalid syntax`,
+		},
+		{
+			name:   "non-pointer",
+			values: url.Values{},
+			v:      SearchQuery{},
+			wantErr: "unsupported form type: goldsert.SearchQuery is not a " +
+				"non-nil pointer",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &formCodec{keyFunc: DottedFormKey}
+
+			err := c.decode(tt.values, tt.v)
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, tt.v)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package goldsert
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+)
+
+// Format identifies one of the serialization formats supported by the core
+// marshaling assertions.
+type Format string
+
+// Formats supported by the core marshaling assertions.
+const (
+	JSON Format = "json"
+	YAML Format = "yaml"
+	XML  Format = "xml"
+)
+
+var errUnknownFormat = errors.New("unknown format")
+
+// goldenName returns the name of golden files used by the marshaling
+// assertions of the format.
+func (f Format) goldenName() string {
+	return "goldsert_" + string(f)
+}
+
+// marshal marshals v with the encoder of the given format, as configured on
+// the Assert instance.
+func (s *Assert) marshal(format Format, v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+
+	switch format {
+	case JSON:
+		err = s.JSONEncoderFunc(&buf).Encode(v)
+	case YAML:
+		err = s.YAMLEncoderFunc(&buf).Encode(v)
+	case XML:
+		var b []byte
+		b, err = s.marshalXML(v)
+		if err == nil && s.CanonicalXML {
+			b, err = canonicalizeXML(s.normalize(b))
+		}
+		buf.Write(b)
+	default:
+		err = fmt.Errorf("%w: %q", errUnknownFormat, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.normalize(buf.Bytes()), nil
+}
+
+// unmarshal unmarshals data into v with the decoder of the given format, as
+// configured on the Assert instance.
+func (s *Assert) unmarshal(format Format, data []byte, v interface{}) error {
+	switch format {
+	case JSON:
+		return s.JSONDecoderFunc(bytes.NewReader(data)).Decode(v)
+	case YAML:
+		return s.YAMLDecoderFunc(bytes.NewReader(data)).Decode(v)
+	case XML:
+		err := s.newXMLDecoder(bytes.NewReader(data)).Decode(v)
+		if err != nil || !s.StrictXMLDecoding {
+			return err
+		}
+
+		return checkXMLKnownFields(data, reflect.TypeOf(v))
+	default:
+		return fmt.Errorf("%w: %q", errUnknownFormat, format)
+	}
+}
+
+// goldenFiles returns the content of all golden files of the given format
+// within the golden file directory, in lexical order of their paths.
+func (s *Assert) goldenFiles(format Format) ([][]byte, error) {
+	name := format.goldenName() + s.Golden.Suffix
+
+	var files [][]byte
+	err := filepath.Walk(s.Golden.Dirname,
+		func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) && path == s.Golden.Dirname {
+					return nil
+				}
+
+				return err
+			}
+			if info.IsDir() || info.Name() != name {
+				return nil
+			}
+
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			files = append(files, s.normalize(data))
+
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
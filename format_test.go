@@ -0,0 +1,87 @@
+package goldsert
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssert_marshal(t *testing.T) {
+	book := &Book{
+		ID:     "cfda163c-d5c1-44a2-909b-5d2ce3a31979",
+		Title:  "The Traveler",
+		Author: &Author{FirstName: "John", LastName: "Twelve Hawks"},
+		Year:   2005,
+	}
+
+	for _, format := range []Format{JSON, YAML, XML} {
+		t.Run(string(format), func(t *testing.T) {
+			gs := New()
+
+			data, err := gs.marshal(format, book)
+			require.NoError(t, err)
+
+			got := &Book{}
+			err = gs.unmarshal(format, data, got)
+			require.NoError(t, err)
+
+			assert.Equal(t, book, got)
+		})
+	}
+}
+
+func TestAssert_marshal_unknownFormat(t *testing.T) {
+	gs := New()
+
+	_, err := gs.marshal(Format("csv"), &Book{})
+	assert.ErrorIs(t, err, errUnknownFormat)
+
+	err = gs.unmarshal(Format("csv"), []byte("id,title\n"), &Book{})
+	assert.ErrorIs(t, err, errUnknownFormat)
+}
+
+func TestAssert_unmarshal_StrictXMLDecoding(t *testing.T) {
+	data := []byte("<Book><id>1</id><isbn>123</isbn></Book>")
+
+	gs := New()
+	err := gs.unmarshal(XML, data, &Book{})
+	assert.NoError(t, err)
+
+	gs.StrictXMLDecoding = true
+	err = gs.unmarshal(XML, data, &Book{})
+	assert.Error(t, err)
+}
+
+func TestAssert_goldenFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goldsert-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"TestB/goldsert_json.golden":     "{\"b\":2}\r\n",
+		"TestA/one/goldsert_json.golden": "{\"a\":1}\n",
+		"TestA/one/goldsert_yaml.golden": "a: 1\n",
+		"TestA/goldsert_json.txt":        "{}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0o644))
+	}
+
+	gs := New()
+	gs.Golden.Dirname = dir
+
+	got, err := gs.goldenFiles(JSON)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("{\"a\":1}\n"), []byte("{\"b\":2}\n")}, got)
+
+	gs.Golden.Dirname = filepath.Join(dir, "missing")
+	got, err = gs.goldenFiles(JSON)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
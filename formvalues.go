@@ -0,0 +1,616 @@
+package goldsert
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	textMarshalerType = reflect.TypeOf(
+		(*encoding.TextMarshaler)(nil),
+	).Elem()
+	errUnsupportedFormType = errors.New("unsupported form type")
+	errUnknownFormKeys     = errors.New("unknown form keys")
+	errMultipleFormValues  = errors.New("multiple form values")
+)
+
+// DottedFormKey is a FormKeyFunc which joins keys with a dot, producing keys
+// like "address.city" and "items.0.name".
+func DottedFormKey(parent, child string) string {
+	return parent + "." + child
+}
+
+// BracketFormKey is a FormKeyFunc which wraps child keys in square brackets,
+// producing keys like "address[city]" and "items[0][name]".
+func BracketFormKey(parent, child string) string {
+	return parent + "[" + child + "]"
+}
+
+// formCodec converts between Go values and url.Values, using "form" struct
+// tags, falling back to "url" struct tags.
+//
+// Structs, maps with string keys, slices and arrays are supported, along with
+// scalar values, and types which implement encoding.TextMarshaler and
+// encoding.TextUnmarshaler. Slices of scalars are stored as repeated values of
+// the same key, while nested structs, maps and slices of structs use keys
+// produced by the key function. Maps must have string keys, and hold scalar
+// values or slices of scalars.
+type formCodec struct {
+	keyFunc func(parent, child string) string
+}
+
+func (c *formCodec) key(parent, child string) string {
+	if parent == "" {
+		return child
+	}
+
+	return c.keyFunc(parent, child)
+}
+
+// encode returns the url.Values representation of v, which must be a struct or
+// a map, or a pointer to one.
+func (c *formCodec) encode(v interface{}) (url.Values, error) {
+	rv := indirectValue(reflect.ValueOf(v))
+	if !rv.IsValid() ||
+		(rv.Kind() != reflect.Struct && rv.Kind() != reflect.Map) ||
+		isFormScalar(rv.Type()) {
+		return nil, fmt.Errorf(
+			"%w: %T is not a struct or map", errUnsupportedFormType, v,
+		)
+	}
+
+	values := url.Values{}
+	err := c.encodeValue(values, "", rv)
+	if err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+func (c *formCodec) encodeValue(
+	values url.Values,
+	key string,
+	rv reflect.Value,
+) error {
+	rv = indirectValue(rv)
+	if !rv.IsValid() {
+		return nil
+	}
+
+	if isFormScalar(rv.Type()) {
+		s, err := formatFormScalar(rv)
+		if err != nil {
+			return err
+		}
+		values.Add(key, s)
+
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		for _, f := range formFieldsOf(rv.Type()) {
+			fv := rv.FieldByIndex(f.index)
+			if f.omitEmpty && fv.IsZero() {
+				continue
+			}
+
+			err := c.encodeValue(values, c.key(key, f.name), fv)
+			if err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf(
+				"%w: %s has non-string keys", errUnsupportedFormType, rv.Type(),
+			)
+		}
+		// Map entries are decoded by taking everything after the key of the
+		// map as the key of the entry, so values must not nest further keys.
+		if !isFormFlat(indirectType(rv.Type().Elem())) {
+			return fmt.Errorf(
+				"%w: %s has values which are not scalars or slices of scalars",
+				errUnsupportedFormType, rv.Type(),
+			)
+		}
+
+		iter := rv.MapRange()
+		for iter.Next() {
+			err := c.encodeValue(
+				values, c.key(key, iter.Key().String()), iter.Value(),
+			)
+			if err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		scalar := isFormScalar(indirectType(rv.Type().Elem()))
+		for i := 0; i < rv.Len(); i++ {
+			elemKey := key
+			if !scalar {
+				elemKey = c.key(key, strconv.Itoa(i))
+			}
+
+			err := c.encodeValue(values, elemKey, rv.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf(
+			"%w: %s at key %q", errUnsupportedFormType, rv.Type(), key,
+		)
+	}
+
+	return nil
+}
+
+// decode sets v, which must be a pointer to a struct or map, from the given
+// url.Values. It returns an error if any of the keys are not used.
+func (c *formCodec) decode(values url.Values, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf(
+			"%w: %T is not a non-nil pointer", errUnsupportedFormType, v,
+		)
+	}
+
+	used := map[string]bool{}
+	err := c.decodeValue(values, used, "", rv.Elem())
+	if err != nil {
+		return err
+	}
+
+	var unknown []string
+	for key := range values {
+		if !used[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+
+		return fmt.Errorf(
+			"%w: %s", errUnknownFormKeys, strings.Join(unknown, ", "),
+		)
+	}
+
+	return nil
+}
+
+func (c *formCodec) decodeValue(
+	values url.Values,
+	used map[string]bool,
+	key string,
+	rv reflect.Value,
+) error {
+	if rv.Kind() == reflect.Ptr {
+		// Only allocate pointers when any keys are used by the value they
+		// point to.
+		elem := reflect.New(rv.Type().Elem())
+		before := len(used)
+
+		err := c.decodeValue(values, used, key, elem.Elem())
+		if err != nil {
+			return err
+		}
+		if len(used) > before {
+			rv.Set(elem)
+		}
+
+		return nil
+	}
+
+	if isFormScalar(rv.Type()) {
+		vals, ok := values[key]
+		if !ok {
+			return nil
+		}
+		if len(vals) > 1 {
+			return fmt.Errorf("%w: %q", errMultipleFormValues, key)
+		}
+		used[key] = true
+
+		return parseFormScalar(vals[0], rv)
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		for _, f := range formFieldsOf(rv.Type()) {
+			fv := fieldByIndexAlloc(rv, f.index)
+			err := c.decodeValue(values, used, c.key(key, f.name), fv)
+			if err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		return c.decodeMap(values, used, key, rv)
+	case reflect.Slice:
+		return c.decodeSlice(values, used, key, rv)
+	case reflect.Array:
+		if isFormScalar(indirectType(rv.Type().Elem())) {
+			vals := values[key]
+			if len(vals) > rv.Len() {
+				return fmt.Errorf("%w: %q", errMultipleFormValues, key)
+			}
+			if len(vals) > 0 {
+				used[key] = true
+			}
+
+			for i, s := range vals {
+				err := parseFormValue(s, rv.Index(i))
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+
+		for i := 0; i < rv.Len(); i++ {
+			err := c.decodeValue(
+				values, used, c.key(key, strconv.Itoa(i)), rv.Index(i),
+			)
+			if err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf(
+			"%w: %s at key %q", errUnsupportedFormType, rv.Type(), key,
+		)
+	}
+
+	return nil
+}
+
+func (c *formCodec) decodeMap(
+	values url.Values,
+	used map[string]bool,
+	key string,
+	rv reflect.Value,
+) error {
+	typ := rv.Type()
+	if typ.Key().Kind() != reflect.String {
+		return fmt.Errorf(
+			"%w: %s has non-string keys", errUnsupportedFormType, typ,
+		)
+	}
+
+	// Map entries are found by matching keys against the key produced for a
+	// placeholder child, and extracting the child key from between the
+	// resulting prefix and suffix.
+	prefix, suffix := "", ""
+	if key != "" {
+		parts := strings.SplitN(c.key(key, "\x00"), "\x00", 2)
+		prefix, suffix = parts[0], parts[1]
+	}
+
+	var children []string
+	for k := range values {
+		if len(k) > len(prefix)+len(suffix) &&
+			strings.HasPrefix(k, prefix) && strings.HasSuffix(k, suffix) {
+			children = append(children, k[len(prefix):len(k)-len(suffix)])
+		}
+	}
+	if len(children) == 0 {
+		return nil
+	}
+	sort.Strings(children)
+
+	if rv.IsNil() {
+		rv.Set(reflect.MakeMapWithSize(typ, len(children)))
+	}
+	for _, child := range children {
+		elem := reflect.New(typ.Elem()).Elem()
+		err := c.decodeValue(values, used, c.key(key, child), elem)
+		if err != nil {
+			return err
+		}
+
+		rv.SetMapIndex(reflect.ValueOf(child).Convert(typ.Key()), elem)
+	}
+
+	return nil
+}
+
+func (c *formCodec) decodeSlice(
+	values url.Values,
+	used map[string]bool,
+	key string,
+	rv reflect.Value,
+) error {
+	typ := rv.Type()
+
+	if isFormScalar(indirectType(typ.Elem())) {
+		vals, ok := values[key]
+		if !ok {
+			return nil
+		}
+		used[key] = true
+
+		s := reflect.MakeSlice(typ, len(vals), len(vals))
+		for i, val := range vals {
+			err := parseFormValue(val, s.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+		rv.Set(s)
+
+		return nil
+	}
+
+	// Elements are decoded by index until an element does not use any keys.
+	s := reflect.MakeSlice(typ, 0, 0)
+	for i := 0; ; i++ {
+		elem := reflect.New(typ.Elem()).Elem()
+		before := len(used)
+
+		err := c.decodeValue(values, used, c.key(key, strconv.Itoa(i)), elem)
+		if err != nil {
+			return err
+		}
+		if len(used) == before {
+			break
+		}
+
+		s = reflect.Append(s, elem)
+	}
+	if s.Len() > 0 {
+		rv.Set(s)
+	}
+
+	return nil
+}
+
+// formField describes a struct field which is encoded to and decoded from form
+// values.
+type formField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+// formFieldsOf returns the form fields of the given struct type. Fields of
+// embedded structs without a tag name are promoted to the parent struct.
+func formFieldsOf(typ reflect.Type) []formField {
+	var fields []formField
+
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+
+		tag, ok := f.Tag.Lookup("form")
+		if !ok {
+			tag = f.Tag.Get("url")
+		}
+		if tag == "-" {
+			continue
+		}
+
+		name, opts := tag, ""
+		if i := strings.Index(tag, ","); i >= 0 {
+			name, opts = tag[:i], tag[i+1:]
+		}
+
+		ft := indirectType(f.Type)
+		if f.Anonymous && name == "" &&
+			ft.Kind() == reflect.Struct && !isFormScalar(ft) {
+			for _, ef := range formFieldsOf(ft) {
+				ef.index = append([]int{i}, ef.index...)
+				fields = append(fields, ef)
+			}
+
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		field := formField{name: name, index: []int{i}}
+		for _, opt := range strings.Split(opts, ",") {
+			if opt == "omitempty" {
+				field.omitEmpty = true
+			}
+		}
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+// fieldByIndexAlloc returns the nested field of rv by index, allocating nil
+// embedded struct pointers along the way.
+func fieldByIndexAlloc(rv reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+
+	return rv
+}
+
+// isFormScalar returns true if values of the given type are stored as a single
+// form value.
+func isFormScalar(typ reflect.Type) bool {
+	if typ.Implements(textMarshalerType) ||
+		reflect.PtrTo(typ).Implements(textUnmarshalerType) {
+		return true
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// isFormFlat returns true if values of the given type are encoded without
+// nesting further keys, as scalars or slices of scalars.
+func isFormFlat(typ reflect.Type) bool {
+	if isFormScalar(typ) {
+		return true
+	}
+
+	switch typ.Kind() {
+	case reflect.Slice, reflect.Array:
+		return isFormScalar(indirectType(typ.Elem()))
+	default:
+		return false
+	}
+}
+
+func formatFormScalar(rv reflect.Value) (string, error) {
+	if m, ok := rv.Interface().(encoding.TextMarshaler); ok {
+		b, err := m.MarshalText()
+
+		return string(b), err
+	}
+	if rv.CanAddr() {
+		if m, ok := rv.Addr().Interface().(encoding.TextMarshaler); ok {
+			b, err := m.MarshalText()
+
+			return string(b), err
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), nil
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(
+			rv.Float(), 'g', -1, rv.Type().Bits(),
+		), nil
+	default:
+		return "", fmt.Errorf("%w: %s", errUnsupportedFormType, rv.Type())
+	}
+}
+
+// parseFormValue parses s into rv, allocating rv if it is a nil pointer.
+func parseFormValue(s string, rv reflect.Value) error {
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		rv = rv.Elem()
+	}
+
+	return parseFormScalar(s, rv)
+}
+
+func parseFormScalar(s string, rv reflect.Value) error {
+	if u, ok := rv.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		rv.SetBool(b)
+	case reflect.String:
+		rv.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetFloat(f)
+	default:
+		return fmt.Errorf("%w: %s", errUnsupportedFormType, rv.Type())
+	}
+
+	return nil
+}
+
+// encodeFormGolden renders the given values as one "key=value" pair per line,
+// sorted by key, with keys and values query escaped.
+func encodeFormGolden(values url.Values) []byte {
+	encoded := values.Encode()
+	if encoded == "" {
+		return []byte{}
+	}
+
+	return []byte(strings.ReplaceAll(encoded, "&", "\n") + "\n")
+}
+
+// decodeFormGolden parses the one "key=value" pair per line format produced by
+// encodeFormGolden. Empty lines are ignored.
+func decodeFormGolden(data []byte) (url.Values, error) {
+	values := url.Values{}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if line == "" {
+			continue
+		}
+
+		pair, err := url.ParseQuery(line)
+		if err != nil {
+			return nil, err
+		}
+		for key, vals := range pair {
+			values[key] = append(values[key], vals...)
+		}
+	}
+
+	return values, nil
+}
+
+func indirectValue(rv reflect.Value) reflect.Value {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return reflect.Value{}
+		}
+		rv = rv.Elem()
+	}
+
+	return rv
+}
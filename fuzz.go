@@ -0,0 +1,80 @@
+//go:build go1.21
+// +build go1.21
+
+package goldsert
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// FuzzUnmarshal fuzzes unmarshaling of the given format into values of type
+// T, seeding the fuzzer with every golden file of the format found in the
+// golden file directory (testdata by default).
+//
+// Inputs which fail to unmarshal into T are ignored. Any input which does
+// unmarshal is marshaled again, and unmarshaled into a new T, which must be
+// equal to the first value. This catches custom unmarshaling code that
+// produces values which do not survive a round-trip. Values which are not
+// equal, but which marshal identically, like NaN floats, are considered equal.
+//
+// Usage:
+//
+//  func FuzzBookJSON(f *testing.F) {
+//      goldsert.FuzzUnmarshal[Book](f, goldsert.JSON)
+//  }
+func FuzzUnmarshal[T any](f *testing.F, format Format) {
+	f.Helper()
+
+	FuzzUnmarshalWith[T](f, global, format)
+}
+
+// FuzzUnmarshalWith is like FuzzUnmarshal, but uses the encoders, decoders
+// and golden file configuration of the given Assert instance.
+func FuzzUnmarshalWith[T any](f *testing.F, s *Assert, format Format) {
+	f.Helper()
+
+	seeds, err := s.goldenFiles(format)
+	require.NoErrorf(f, err,
+		"failed to read %s golden files from %s", format, s.Golden.Dirname,
+	)
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		v := new(T)
+		if s.unmarshal(format, data, v) != nil {
+			return
+		}
+
+		marshaled, err := s.marshal(format, v)
+		require.NoErrorf(t, err,
+			"failed to %s marshal %T unmarshaled from input:\n%s",
+			format, v, data,
+		)
+
+		got := new(T)
+		err = s.unmarshal(format, marshaled, got)
+		require.NoErrorf(t, err,
+			"failed to %s unmarshal %T from marshaled value:\n%s",
+			format, got, marshaled,
+		)
+
+		if assert.ObjectsAreEqual(v, got) {
+			return
+		}
+		remarshaled, err := s.marshal(format, got)
+		if err == nil && bytes.Equal(marshaled, remarshaled) {
+			return
+		}
+
+		assert.Equal(t, v, got,
+			"unmarshaling marshaled value does not match value unmarshaled "+
+				"from input:\n%s", data,
+		)
+	})
+}
@@ -0,0 +1,29 @@
+//go:build go1.21
+// +build go1.21
+
+package goldsert
+
+import "testing"
+
+func FuzzUnmarshal_BookJSON(f *testing.F) {
+	FuzzUnmarshal[Book](f, JSON)
+}
+
+func FuzzUnmarshal_BookYAML(f *testing.F) {
+	FuzzUnmarshal[Book](f, YAML)
+}
+
+func FuzzUnmarshal_BookXML(f *testing.F) {
+	FuzzUnmarshal[Book](f, XML)
+}
+
+func FuzzUnmarshal_ArticleJSON(f *testing.F) {
+	FuzzUnmarshal[Article](f, JSON)
+}
+
+func FuzzUnmarshalWith_ArticleXML(f *testing.F) {
+	gs := New()
+	gs.StrictXMLDecoding = true
+
+	FuzzUnmarshalWith[Article](f, gs, XML)
+}
@@ -123,3 +123,124 @@ func XMLMarshalingP(t *testing.T, v, want interface{}) {
 
 	global.XMLMarshalingP(t, v, want)
 }
+
+// JSONLinesMarshaling asserts that each item of the given "v" slice JSON
+// marshals to one line of an expected JSON Lines stream fetched from a golden
+// file on disk, and then verifies that unmarshaling each line of the stream
+// produces a slice that is equal to "v".
+//
+// Used for items that do NOT change when they are marshaled and unmarshaled.
+func JSONLinesMarshaling(t *testing.T, v interface{}) {
+	t.Helper()
+
+	global.JSONLinesMarshaling(t, v)
+}
+
+// JSONLinesMarshalingP asserts that each item of the given "v" slice JSON
+// marshals to one line of an expected JSON Lines stream fetched from a golden
+// file on disk, and then verifies that unmarshaling each line of the stream
+// produces a slice that is equal to "want".
+//
+// Used for items that change when they are marshaled and unmarshaled.
+func JSONLinesMarshalingP(t *testing.T, v, want interface{}) {
+	t.Helper()
+
+	global.JSONLinesMarshalingP(t, v, want)
+}
+
+// YAMLDocumentsMarshaling asserts that the given "docs" values YAML marshal to
+// an expected multi-document YAML stream fetched from a golden file on disk,
+// and then verifies that unmarshaling each document of the stream produces a
+// value that is equal to the corresponding value in "docs".
+//
+// Used for objects that do NOT change when they are marshaled and unmarshaled.
+func YAMLDocumentsMarshaling(t *testing.T, docs ...interface{}) {
+	t.Helper()
+
+	global.YAMLDocumentsMarshaling(t, docs...)
+}
+
+// YAMLDocumentsMarshalingP asserts that the given "docs" values YAML marshal to
+// an expected multi-document YAML stream fetched from a golden file on disk,
+// and then verifies that unmarshaling each document of the stream produces a
+// value that is equal to the corresponding value in "want".
+//
+// Used for objects that change when they are marshaled and unmarshaled.
+func YAMLDocumentsMarshalingP(t *testing.T, docs, want []interface{}) {
+	t.Helper()
+
+	global.YAMLDocumentsMarshalingP(t, docs, want)
+}
+
+// FormMarshaling asserts that the given "v" value encodes to expected
+// application/x-www-form-urlencoded values fetched from a golden file on disk,
+// and then verifies that decoding the golden file produces a value that is
+// equal to "v".
+//
+// Used for objects that do NOT change when they are encoded and decoded.
+func FormMarshaling(t *testing.T, v interface{}) {
+	t.Helper()
+
+	global.FormMarshaling(t, v)
+}
+
+// FormMarshalingP asserts that the given "v" value encodes to expected
+// application/x-www-form-urlencoded values fetched from a golden file on disk,
+// and then verifies that decoding the golden file produces a value that is
+// equal to "want".
+//
+// Used for objects that change when they are encoded and decoded.
+func FormMarshalingP(t *testing.T, v, want interface{}) {
+	t.Helper()
+
+	global.FormMarshalingP(t, v, want)
+}
+
+// Dump asserts that the given "v" value renders to an expected Go-syntax
+// representation fetched from a golden file on disk. It is intended for types
+// which are not serialized in any other way, and there is no unmarshaling
+// stage.
+func Dump(t *testing.T, v interface{}) {
+	t.Helper()
+
+	global.Dump(t, v)
+}
+
+// TemplateRendering asserts that executing the given "tmpl" template with
+// "data" renders the expected output fetched from a golden file on disk.
+//
+// Both *text/template.Template and *html/template.Template are supported.
+func TemplateRendering(t *testing.T, tmpl Template, data interface{}) {
+	t.Helper()
+
+	global.TemplateRendering(t, tmpl, data)
+}
+
+// SchemaSnapshot asserts that the JSON Schema derived from the type of the
+// given "v" value matches an expected JSON Schema fetched from a golden file on
+// disk, revealing changes to the JSON representation of the type.
+func SchemaSnapshot(t *testing.T, v interface{}) {
+	t.Helper()
+
+	global.SchemaSnapshot(t, v)
+}
+
+// RoundTripProperty asserts that random values generated by "gen" marshal and
+// unmarshal with the given format, producing a value equal to the generated
+// one. Failing values are shrunk to a minimal failing value before being
+// reported.
+func RoundTripProperty(t *testing.T, format Format, gen *Generator) {
+	t.Helper()
+
+	global.RoundTripProperty(t, format, gen)
+}
+
+// CrossFormatConsistency asserts that the JSON, YAML and XML golden files of
+// the test carry the same data, reporting fields which are present in one
+// format but missing in another, and values which differ. When no formats are
+// given, all golden files which exist for the test are compared.
+func CrossFormatConsistency(t *testing.T, formats ...Format) {
+	t.Helper()
+
+	global.CrossFormatConsistency(t, formats...)
+}
@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"encoding/xml"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"regexp"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 )
 
@@ -104,6 +107,41 @@ func (s *Comic) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	return nil
 }
 
+// runTest runs f as a separate test with the same name as t, so assertions
+// expected to fail can be tested without failing t. It returns true if the
+// test passed, along with its output, which includes failure messages.
+func runTest(t *testing.T, f func(t *testing.T)) (bool, string) {
+	t.Helper()
+
+	out, err := ioutil.TempFile("", "goldsert-output-")
+	require.NoError(t, err)
+	defer os.Remove(out.Name())
+	defer out.Close()
+
+	stdout := os.Stdout
+	os.Stdout = out
+	ok := testing.RunTests(regexp.MatchString, []testing.InternalTest{
+		{Name: t.Name(), F: f},
+	})
+	os.Stdout = stdout
+
+	output, err := ioutil.ReadFile(out.Name())
+	require.NoError(t, err)
+
+	return ok, string(output)
+}
+
+// captureLogs makes gs log notices to the returned buffer, rather than to the
+// test.
+func captureLogs(gs *Assert) *bytes.Buffer {
+	var buf bytes.Buffer
+	gs.logf = func(_ *testing.T, format string, args ...interface{}) {
+		fmt.Fprintf(&buf, format+"\n", args...)
+	}
+
+	return &buf
+}
+
 func boolPtr(b bool) *bool {
 	return &b
 }
@@ -308,3 +346,98 @@ func TestXMLMarshalingP(t *testing.T) {
 		})
 	}
 }
+
+func TestJSONLinesMarshaling(t *testing.T) {
+	for _, tt := range jsonLinesTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			JSONLinesMarshaling(t, tt.v)
+		})
+	}
+}
+
+func TestJSONLinesMarshalingP(t *testing.T) {
+	for _, tt := range jsonLinesPTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			JSONLinesMarshalingP(t, tt.v, tt.want)
+		})
+	}
+}
+
+func TestYAMLDocumentsMarshaling(t *testing.T) {
+	for _, tt := range yamlDocumentsTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			YAMLDocumentsMarshaling(t, tt.docs...)
+		})
+	}
+}
+
+func TestYAMLDocumentsMarshalingP(t *testing.T) {
+	for _, tt := range yamlDocumentsPTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			YAMLDocumentsMarshalingP(t, tt.docs, tt.want)
+		})
+	}
+}
+
+func TestFormMarshaling(t *testing.T) {
+	for _, tt := range formTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			FormMarshaling(t, tt.v)
+		})
+	}
+}
+
+func TestFormMarshalingP(t *testing.T) {
+	for _, tt := range formPTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			FormMarshalingP(t, tt.v, tt.want)
+		})
+	}
+}
+
+func TestDump(t *testing.T) {
+	for _, tt := range dumpTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			Dump(t, tt.v)
+		})
+	}
+}
+
+func TestTemplateRendering(t *testing.T) {
+	for _, tt := range templateTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			TemplateRendering(t, tt.tmpl, tt.data)
+		})
+	}
+}
+
+func TestSchemaSnapshot(t *testing.T) {
+	for _, tt := range schemaSnapshotTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			SchemaSnapshot(t, tt.v)
+		})
+	}
+}
+
+func TestRoundTripProperty(t *testing.T) {
+	for _, tt := range roundTripPropertyTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := *tt.gen
+			gen.Seed = 42
+
+			RoundTripProperty(t, tt.format, &gen)
+		})
+	}
+}
+
+func TestCrossFormatConsistency(t *testing.T) {
+	for _, tt := range crossFormatConsistencyTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			JSONMarshaling(t, tt.v)
+			YAMLMarshaling(t, tt.v)
+			XMLMarshaling(t, tt.v)
+
+			CrossFormatConsistency(t)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package goldsert
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// assertIdempotent asserts that "got", as unmarshaled from the golden file of
+// the given format, marshals back to the content of the golden file. It is
+// the third stage of the marshaling assertions when CheckIdempotency is
+// enabled.
+func (s *Assert) assertIdempotent(
+	t *testing.T,
+	format Format,
+	got interface{},
+	gold []byte,
+) {
+	t.Helper()
+
+	remarshaled, err := s.marshal(format, got)
+	require.NoErrorf(t, err,
+		"failed to %s marshal %T unmarshaled from %s",
+		format, got, s.Golden.FileP(t, format.goldenName()),
+	)
+
+	msg := "marshaling value unmarshaled from golden file does not " +
+		"reproduce golden file"
+	switch format {
+	case JSON:
+		assert.JSONEq(t, string(gold), string(remarshaled), msg)
+	case YAML:
+		assert.YAMLEq(t, string(gold), string(remarshaled), msg)
+	default:
+		assert.Equal(t, string(gold), string(remarshaled), msg)
+	}
+}
@@ -0,0 +1,99 @@
+package goldsert
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssert_JSONMarshalingP_CheckIdempotency(t *testing.T) {
+	for _, tt := range marshalingPTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+			gs.CheckIdempotency = true
+
+			gs.JSONMarshalingP(t, tt.v, tt.want)
+		})
+	}
+}
+
+func TestAssert_YAMLMarshalingP_CheckIdempotency(t *testing.T) {
+	for _, tt := range marshalingPTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+			gs.CheckIdempotency = true
+
+			gs.YAMLMarshalingP(t, tt.v, tt.want)
+		})
+	}
+}
+
+func TestAssert_XMLMarshalingP_CheckIdempotency(t *testing.T) {
+	for _, tt := range marshalingPTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+			gs.CheckIdempotency = true
+
+			gs.XMLMarshalingP(t, tt.v, tt.want)
+		})
+	}
+}
+
+func TestAssert_XMLMarshalingP_CheckIdempotency_CanonicalXML(t *testing.T) {
+	for _, tt := range marshalingPTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+			gs.CheckIdempotency = true
+			gs.CanonicalXML = true
+
+			gs.XMLMarshalingP(t, tt.v, tt.want)
+		})
+	}
+}
+
+// lossyTag marshals its color, but drops it when unmarshaled, so it does not
+// survive a round trip through its golden file.
+type lossyTag struct {
+	Name  string
+	Color string
+}
+
+func (s *lossyTag) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{"name": s.Name, "color": s.Color})
+}
+
+func (s *lossyTag) UnmarshalJSON(data []byte) error {
+	var m map[string]string
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	*s = lossyTag{Name: m["name"]}
+
+	return nil
+}
+
+func TestAssert_JSONMarshalingP_CheckIdempotency_Lossy(t *testing.T) {
+	v := &lossyTag{Name: "fiction", Color: "red"}
+	want := &lossyTag{Name: "fiction"}
+
+	ok, output := runTest(t, func(t *testing.T) {
+		gs := New()
+		gs.JSONMarshalingP(t, v, want)
+	})
+	assert.True(t, ok, "without idempotency check:\n%s", output)
+
+	ok, output = runTest(t, func(t *testing.T) {
+		gs := New()
+		gs.CheckIdempotency = true
+		gs.JSONMarshalingP(t, v, want)
+	})
+	assert.False(t, ok)
+	assert.Contains(t, output,
+		"marshaling value unmarshaled from golden file does not "+
+			"reproduce golden file",
+	)
+	assert.Contains(t, output,
+		`actual  : map[string]interface {}{"color":"", "name":"fiction"}`,
+	)
+}
@@ -0,0 +1,124 @@
+package goldsert
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// JSONLinesMarshaling asserts that each item of the given "v" slice JSON
+// marshals to one line of an expected JSON Lines stream fetched from a golden
+// file on disk, and then verifies that unmarshaling each line of the stream
+// produces a slice that is equal to "v".
+//
+// Used for items that do NOT change when they are marshaled and unmarshaled.
+func (s *Assert) JSONLinesMarshaling(t *testing.T, v interface{}) {
+	t.Helper()
+
+	s.JSONLinesMarshalingP(t, v, v)
+}
+
+// JSONLinesMarshalingP asserts that each item of the given "v" slice JSON
+// marshals to one line of an expected JSON Lines stream fetched from a golden
+// file on disk, and then verifies that unmarshaling each line of the stream
+// produces a slice that is equal to "want".
+//
+// Used for items that change when they are marshaled and unmarshaled.
+func (s *Assert) JSONLinesMarshalingP(t *testing.T, v, want interface{}) {
+	t.Helper()
+
+	items := requireSlice(t, v)
+
+	var buf bytes.Buffer
+	enc := s.JSONLinesEncoderFunc(&buf)
+	for i := 0; i < items.Len(); i++ {
+		item := items.Index(i).Interface()
+		err := enc.Encode(item)
+		require.NoErrorf(t, err,
+			"failed to JSON marshal line %d %T: %+v", i+1, item, item,
+		)
+	}
+
+	marshaled := s.normalize(buf.Bytes())
+	gold := s.GoldenP(t, "goldsert_jsonl", marshaled, jsonLinesEqual)
+
+	lines := splitLines(marshaled)
+	goldLines := splitLines(gold)
+	assert.Lenf(t, lines, len(goldLines),
+		"JSON Lines stream does not have the same number of lines as %s",
+		s.Golden.FileP(t, "goldsert_jsonl"),
+	)
+	for i := 0; i < len(lines) && i < len(goldLines); i++ {
+		assert.JSONEqf(t, string(goldLines[i]), string(lines[i]),
+			"line %d of JSON Lines stream does not match golden file", i+1,
+		)
+	}
+
+	wantItems := requireSlice(t, want)
+	itemType := wantItems.Type().Elem()
+
+	got := reflect.MakeSlice(reflect.SliceOf(itemType), 0, len(goldLines))
+	for i, line := range goldLines {
+		item := reflect.New(itemType)
+		dec := s.JSONDecoderFunc(bytes.NewReader(line))
+		err := dec.Decode(item.Interface())
+		require.NoErrorf(t, err,
+			"failed to JSON unmarshal %s from line %d of %s",
+			itemType, i+1, s.Golden.FileP(t, "goldsert_jsonl"),
+		)
+
+		var extra json.RawMessage
+		err = dec.Decode(&extra)
+		if !errors.Is(err, io.EOF) {
+			require.FailNowf(t,
+				"JSON Lines line holds more than one value",
+				"line %d of %s has data after the first JSON value",
+				i+1, s.Golden.FileP(t, "goldsert_jsonl"),
+			)
+		}
+		got = reflect.Append(got, item.Elem())
+	}
+
+	assert.Equal(t, wantItems.Len(), got.Len(),
+		"unmarshaling from golden file does not produce expected number of "+
+			"items",
+	)
+	for i := 0; i < wantItems.Len() && i < got.Len(); i++ {
+		wantItem := wantItems.Index(i).Interface()
+		assert.Equalf(t, wantItem, got.Index(i).Interface(),
+			"unmarshaling line %d from golden file does not match expected "+
+				"object", i+1,
+		)
+	}
+}
+
+// requireSlice returns the slice or array value of v, dereferencing it if it is
+// a pointer, and fails the test if v is not a slice or array.
+func requireSlice(t *testing.T, v interface{}) reflect.Value {
+	t.Helper()
+
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		require.FailNowf(t,
+			"only slice types can be asserted",
+			"%T is not a slice type", v,
+		)
+	}
+
+	return rv
+}
+
+// splitLines splits data into lines, ignoring a single trailing line break.
+func splitLines(data []byte) [][]byte {
+	if len(data) == 0 {
+		return nil
+	}
+
+	return bytes.Split(bytes.TrimSuffix(data, []byte{10}), []byte{10})
+}
@@ -0,0 +1,194 @@
+package goldsert
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var jsonLinesTestCases = []struct {
+	name string
+	v    interface{}
+}{
+	{
+		name: "empty slice",
+		v:    []*Book{},
+	},
+	{
+		name: "ints",
+		v:    []int{1, 2, 42},
+	},
+	{
+		name: "strings",
+		v:    []string{"hello", "world"},
+	},
+	{
+		name: "structs",
+		v: []Book{
+			{
+				ID:    "cfda163c-d5c1-44a2-909b-5d2ce3a31979",
+				Title: "The Traveler",
+			},
+			{
+				ID:    "f9b8d5b4-42b8-44c3-b8e1-3c3e4eb8fc2f",
+				Title: "The Dark River",
+				Year:  2007,
+			},
+		},
+	},
+	{
+		name: "struct pointers",
+		v: []*Book{
+			{
+				ID:    "cfda163c-d5c1-44a2-909b-5d2ce3a31979",
+				Title: "The Traveler",
+				Author: &Author{
+					FirstName: "John",
+					LastName:  "Twelve Hawks",
+				},
+				Year: 2005,
+			},
+			{},
+		},
+	},
+	{
+		name: "slice pointer",
+		v: &[]*Book{
+			{
+				ID:    "cfda163c-d5c1-44a2-909b-5d2ce3a31979",
+				Title: "The Traveler",
+			},
+		},
+	},
+	{
+		name: "custom marshaling",
+		v: []*Comic{
+			{
+				ID:    "2fd5af35-b85e-4f03-8eba-524be28d7a5b",
+				Name:  "Hello World!",
+				Issue: "Forty Two",
+			},
+			{
+				ID:    "8e5d5a07-4ac6-4e0b-8a0c-6f5c0b6e4b45",
+				Name:  "Goodbye World!",
+				Issue: "Forty Three",
+			},
+		},
+	},
+}
+
+var jsonLinesPTestCases = []struct {
+	name string
+	v    interface{}
+	want interface{}
+}{
+	{
+		name: "empty slice",
+		v:    []*Article{},
+		want: []*Article{},
+	},
+	{
+		name: "structs",
+		v: []*Article{
+			{
+				ID:    "10eec54d-e30a-4428-be18-01095d889126",
+				Title: "Time Travel",
+				Author: &Author{
+					FirstName: "Doc",
+					LastName:  "Brown",
+				},
+				Date:  &articleDate,
+				Rank:  8,
+				order: 16,
+			},
+			{
+				ID:    "a4d3f1f6-0d4c-4c73-9b3b-6f0c3c4ae0ce",
+				Title: "Flux Capacitors",
+				Rank:  2,
+			},
+		},
+		want: []*Article{
+			{
+				ID:    "10eec54d-e30a-4428-be18-01095d889126",
+				Title: "Time Travel",
+				Author: &Author{
+					FirstName: "Doc",
+					LastName:  "Brown",
+				},
+				Date: &articleDate,
+			},
+			{
+				ID:    "a4d3f1f6-0d4c-4c73-9b3b-6f0c3c4ae0ce",
+				Title: "Flux Capacitors",
+			},
+		},
+	},
+	{
+		name: "custom marshaling",
+		v: []*Comic{
+			{
+				ID:      "2fd5af35-b85e-4f03-8eba-524be28d7a5b",
+				Name:    "Hello World!",
+				Issue:   "Forty Two",
+				Ignored: "don't pay attention to this :)",
+			},
+		},
+		want: &[]*Comic{
+			{
+				ID:    "2fd5af35-b85e-4f03-8eba-524be28d7a5b",
+				Name:  "Hello World!",
+				Issue: "Forty Two",
+			},
+		},
+	},
+}
+
+func TestAssert_JSONLinesMarshaling(t *testing.T) {
+	for _, tt := range jsonLinesTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+
+			gs.JSONLinesMarshaling(t, tt.v)
+		})
+	}
+}
+
+func TestAssert_JSONLinesMarshalingP(t *testing.T) {
+	for _, tt := range jsonLinesPTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+
+			gs.JSONLinesMarshalingP(t, tt.v, tt.want)
+		})
+	}
+}
+
+func TestAssert_JSONLinesMarshaling_TrailingData(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goldsert-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	gs := New()
+	gs.Golden.Dirname = dir
+	gs.Golden.UpdateFunc = func() bool { return false }
+	gs.UpdateFilterFunc = nil
+	gs.CreateMissingFunc = nil
+	gs.ReviewFunc = nil
+	file := gs.Golden.FileP(t, "goldsert_jsonl")
+	require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+	err = ioutil.WriteFile(file, []byte("1\n2 3\n"), 0o644)
+	require.NoError(t, err)
+
+	ok, output := runTest(t, func(t *testing.T) {
+		gs.JSONLinesMarshalingP(t, []int{1, 2}, []int{1, 2})
+	})
+
+	assert.False(t, ok, "trailing data on a line must fail the test")
+	assert.Contains(t, output, "line 2 of "+file+
+		" has data after the first JSON value",
+	)
+}
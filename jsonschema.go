@@ -0,0 +1,89 @@
+package goldsert
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// JSONSchemaValidator validates JSON documents against a JSON Schema.
+//
+// The github.com/jimeh/go-goldsert/jsonschema module provides an
+// implementation supporting JSON Schema draft 2020-12, with schemas loaded
+// from local files.
+type JSONSchemaValidator interface {
+	ValidateJSON(data []byte) error
+}
+
+// JSONSchemaValidatorFunc is an adapter to allow the use of ordinary functions
+// as a JSONSchemaValidator.
+type JSONSchemaValidatorFunc func(data []byte) error
+
+// ValidateJSON calls f(data).
+func (f JSONSchemaValidatorFunc) ValidateJSON(data []byte) error {
+	return f(data)
+}
+
+// SetJSONSchema registers the given JSON Schema for the type of "v", so JSON
+// marshaled output and JSON golden files of values of that type must conform
+// to the schema. Pointer types are dereferenced, so registering a schema for
+// *Book also applies to Book, and vice versa.
+func (s *Assert) SetJSONSchema(v interface{}, schema JSONSchemaValidator) {
+	if s.JSONSchemas == nil {
+		s.JSONSchemas = map[reflect.Type]JSONSchemaValidator{}
+	}
+
+	s.JSONSchemas[indirectType(reflect.TypeOf(v))] = schema
+}
+
+// WithJSONSchema returns a shallow copy of the Assert instance with the given
+// JSON Schema set, which JSON marshaled output and JSON golden files of all
+// values must conform to. Useful for specifying a schema per call:
+//
+//  gs.WithJSONSchema(schema).JSONMarshaling(t, v)
+func (s *Assert) WithJSONSchema(schema JSONSchemaValidator) *Assert {
+	c := *s
+	c.JSONSchema = schema
+
+	return &c
+}
+
+// jsonSchemaFor returns the JSON Schema which JSON of the given value must
+// conform to, or nil if there is none.
+func (s *Assert) jsonSchemaFor(v interface{}) JSONSchemaValidator {
+	if s.JSONSchema != nil {
+		return s.JSONSchema
+	}
+	if v == nil || s.JSONSchemas == nil {
+		return nil
+	}
+
+	return s.JSONSchemas[indirectType(reflect.TypeOf(v))]
+}
+
+// validateJSONSchema asserts that both marshaled JSON and the JSON golden file
+// conform to the JSON Schema of the given value, if any.
+func (s *Assert) validateJSONSchema(
+	t *testing.T,
+	v interface{},
+	marshaled, gold []byte,
+) {
+	t.Helper()
+
+	schema := s.jsonSchemaFor(v)
+	if schema == nil {
+		return
+	}
+
+	err := schema.ValidateJSON(marshaled)
+	assert.NoErrorf(t, err,
+		"marshaled JSON of %T does not conform to JSON Schema", v,
+	)
+
+	err = schema.ValidateJSON(gold)
+	assert.NoErrorf(t, err,
+		"golden file %s does not conform to JSON Schema",
+		s.Golden.FileP(t, "goldsert_json"),
+	)
+}
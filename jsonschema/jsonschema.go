@@ -0,0 +1,76 @@
+// Package jsonschema provides JSON Schema validation of JSON golden files for
+// goldsert.
+//
+// It lives in its own module so that the core goldsert module does not depend
+// on a JSON Schema library.
+//
+// Schemas are loaded from local files, and default to JSON Schema draft
+// 2020-12 unless they declare a different draft via the "$schema" keyword.
+// Loaded schemas implement goldsert.JSONSchemaValidator, and validation errors
+// report the JSON Pointer location of each violation within the document.
+//
+// Usage
+//
+//  func TestBookMarshaling(t *testing.T) {
+//      gs := goldsert.New()
+//      gs.SetJSONSchema(&Book{}, jsonschema.MustLoad("schemas/book.json"))
+//
+//      gs.JSONMarshaling(t, &Book{ID: "42", Title: "The Traveler"})
+//  }
+//
+// A schema can also be specified for a single call:
+//
+//  gs.WithJSONSchema(schema).JSONMarshaling(t, book)
+package jsonschema
+
+import (
+	"bytes"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// Schema is a compiled JSON Schema, which JSON documents can be validated
+// against.
+type Schema struct {
+	schema *jsonschema.Schema
+}
+
+// Load compiles the JSON Schema in the file at the given path. Any "$ref"
+// references to other local files are resolved relative to the path.
+func Load(path string) (*Schema, error) {
+	c := jsonschema.NewCompiler()
+	c.DefaultDraft(jsonschema.Draft2020)
+
+	schema, err := c.Compile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Schema{schema: schema}, nil
+}
+
+// MustLoad is like Load, but panics if the schema cannot be loaded. It
+// simplifies loading schemas in package-level variables and test setup.
+func MustLoad(path string) *Schema {
+	s, err := Load(path)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+// ValidateJSON validates the given JSON document against the schema. The
+// returned error lists each violation along with its location within the
+// document, for example:
+//
+//  jsonschema validation failed with 'file:///schemas/book.json#'
+//  - at '/author/first_name': minLength: got 1, want 2
+func (s *Schema) ValidateJSON(data []byte) error {
+	v, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	return s.schema.Validate(v)
+}
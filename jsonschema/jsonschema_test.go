@@ -0,0 +1,117 @@
+package jsonschema
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{
+			name: "valid schema",
+			path: "testdata/book.json",
+		},
+		{
+			name:    "invalid schema",
+			path:    "testdata/invalid.json",
+			wantErr: "jsonschema validation failed",
+		},
+		{
+			name:    "missing file",
+			path:    "testdata/missing.json",
+			wantErr: "missing.json",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(tt.path)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				assert.Nil(t, got)
+			} else {
+				require.NoError(t, err)
+				assert.NotNil(t, got)
+			}
+		})
+	}
+}
+
+func TestMustLoad(t *testing.T) {
+	assert.NotPanics(t, func() {
+		MustLoad("testdata/book.json")
+	})
+	assert.Panics(t, func() {
+		MustLoad("testdata/missing.json")
+	})
+}
+
+func TestSchema_ValidateJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		wantErrs []string
+	}{
+		{
+			name: "valid",
+			data: `{
+  "id": "cfda163c-d5c1-44a2-909b-5d2ce3a31979",
+  "title": "The Traveler",
+  "author": {"first_name": "John", "last_name": "Twelve Hawks"},
+  "year": 2005
+}`,
+		},
+		{
+			name: "missing required property",
+			data: `{"id": "cfda163c-d5c1-44a2-909b-5d2ce3a31979"}`,
+			wantErrs: []string{
+				"- at '': missing property 'title'",
+			},
+		},
+		{
+			name: "nested violations",
+			data: `{
+  "id": "cfda163c-d5c1-44a2-909b-5d2ce3a31979",
+  "title": "The Traveler",
+  "author": {"first_name": "J", "middle_name": "T"},
+  "year": 2005.5
+}`,
+			wantErrs: []string{
+				"- at '/author': additional properties 'middle_name' not " +
+					"allowed",
+				"- at '/author/first_name': minLength: got 1, want 2",
+				"- at '/year': got number, want integer",
+			},
+		},
+		{
+			name:     "invalid JSON",
+			data:     `{"id": `,
+			wantErrs: []string{"unexpected EOF"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := MustLoad(filepath.Join("testdata", "book.json"))
+
+			err := schema.ValidateJSON([]byte(tt.data))
+
+			if len(tt.wantErrs) == 0 {
+				assert.NoError(t, err)
+
+				return
+			}
+			require.Error(t, err)
+			for _, want := range tt.wantErrs {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+}
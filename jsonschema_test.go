@@ -0,0 +1,132 @@
+package goldsert
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// requireKeysSchema returns a JSONSchemaValidator which requires the given keys
+// to be present in a JSON object, and records the number of validations.
+func requireKeysSchema(calls *int, keys ...string) JSONSchemaValidator {
+	return JSONSchemaValidatorFunc(func(data []byte) error {
+		*calls++
+
+		var obj map[string]interface{}
+		err := json.Unmarshal(data, &obj)
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if _, ok := obj[key]; !ok {
+				return errors.New("missing property '" + key + "'")
+			}
+		}
+
+		return nil
+	})
+}
+
+func TestAssert_JSONMarshaling_JSONSchemas(t *testing.T) {
+	book := &Book{
+		ID:    "cfda163c-d5c1-44a2-909b-5d2ce3a31979",
+		Title: "The Traveler",
+	}
+	var bookCalls, authorCalls int
+
+	gs := New()
+	gs.SetJSONSchema(Book{}, requireKeysSchema(&bookCalls, "id", "title"))
+	gs.SetJSONSchema(
+		&Author{}, requireKeysSchema(&authorCalls, "first_name"),
+	)
+
+	gs.JSONMarshaling(t, book)
+
+	assert.Equal(t, 2, bookCalls)
+	assert.Equal(t, 0, authorCalls)
+}
+
+func TestAssert_JSONMarshaling_WithJSONSchema(t *testing.T) {
+	author := &Author{FirstName: "John", LastName: "Twelve Hawks"}
+	var calls, bookCalls int
+
+	gs := New()
+	gs.SetJSONSchema(&Author{}, requireKeysSchema(&bookCalls, "id"))
+
+	gs.WithJSONSchema(
+		requireKeysSchema(&calls, "first_name", "last_name"),
+	).JSONMarshaling(t, author)
+
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, 0, bookCalls)
+	assert.Nil(t, gs.JSONSchema, "original Assert instance was modified")
+}
+
+type stubSchema struct {
+	name string
+}
+
+func (s *stubSchema) ValidateJSON([]byte) error {
+	return nil
+}
+
+func TestAssert_jsonSchemaFor(t *testing.T) {
+	bookSchema := &stubSchema{name: "book"}
+	globalSchema := &stubSchema{name: "global"}
+
+	tests := []struct {
+		name    string
+		schemas map[reflect.Type]JSONSchemaValidator
+		schema  JSONSchemaValidator
+		v       interface{}
+		want    JSONSchemaValidator
+	}{
+		{
+			name: "no schemas",
+			v:    &Book{},
+		},
+		{
+			name: "pointer to registered type",
+			schemas: map[reflect.Type]JSONSchemaValidator{
+				reflect.TypeOf(Book{}): bookSchema,
+			},
+			v:    &Book{},
+			want: bookSchema,
+		},
+		{
+			name: "unregistered type",
+			schemas: map[reflect.Type]JSONSchemaValidator{
+				reflect.TypeOf(Book{}): bookSchema,
+			},
+			v: &Author{},
+		},
+		{
+			name: "nil value",
+			schemas: map[reflect.Type]JSONSchemaValidator{
+				reflect.TypeOf(Book{}): bookSchema,
+			},
+			v: nil,
+		},
+		{
+			name: "schema for all values",
+			schemas: map[reflect.Type]JSONSchemaValidator{
+				reflect.TypeOf(Book{}): bookSchema,
+			},
+			schema: globalSchema,
+			v:      &Book{},
+			want:   globalSchema,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := &Assert{JSONSchemas: tt.schemas, JSONSchema: tt.schema}
+
+			got := gs.jsonSchemaFor(tt.v)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
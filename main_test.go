@@ -0,0 +1,10 @@
+package goldsert
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(Run(m))
+}
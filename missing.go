@@ -0,0 +1,69 @@
+package goldsert
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// EnvCreateMissingFunc checks if the GOLDSERT_CREATE_MISSING environment
+// variable is set to one of "1", "y", "t", "yes", "on", or "true".
+//
+// This is the default CreateMissingFunc of Assert.
+func EnvCreateMissingFunc() bool {
+	return containsString(truthyStrings, os.Getenv("GOLDSERT_CREATE_MISSING"))
+}
+
+// createMissing writes data to the named golden file of the test if it does
+// not exist, when create missing mode is enabled as reported by
+// CreateMissingFunc, logging a notice. The test fails if the golden file is to
+// be created in a CI environment.
+func (s *Assert) createMissing(t *testing.T, name string, data []byte) {
+	t.Helper()
+
+	if s.CreateMissingFunc == nil || !s.CreateMissingFunc() {
+		return
+	}
+
+	file := s.Golden.FileP(t, name)
+	if _, err := os.Stat(file); !os.IsNotExist(err) {
+		return
+	}
+	s.refuseCIUpdate(t)
+
+	created, err := s.createFile(file, data)
+	require.NoErrorf(t, err, "failed to create golden file %s", file)
+
+	if created {
+		updated.add(file, updateCreated)
+		s.log(t, "goldsert: created missing golden file: %s", file)
+	}
+}
+
+// createFile writes data to the given golden file, only if it does not exist.
+// It returns true if the golden file was created.
+func (s *Assert) createFile(file string, data []byte) (bool, error) {
+	err := os.MkdirAll(filepath.Dir(file), s.Golden.DirMode)
+	if err != nil {
+		return false, err
+	}
+
+	f, err := os.OpenFile(
+		file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, s.Golden.FileMode,
+	)
+	if os.IsExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	return err == nil, err
+}
@@ -0,0 +1,145 @@
+package goldsert
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssert_JSONMarshaling_CreateMissingFunc(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goldsert-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, tt := range marhalingTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+			gs.Golden.Dirname = dir
+			gs.Golden.UpdateFunc = func() bool { return false }
+			gs.UpdateFilterFunc = nil
+			gs.ReviewFunc = nil
+			gs.CIFunc = nil
+			gs.CreateMissingFunc = func() bool { return true }
+			logs := captureLogs(gs)
+
+			gs.JSONMarshaling(t, tt.v)
+
+			file := gs.Golden.FileP(t, "goldsert_json")
+			assert.FileExists(t, file)
+			assert.Equal(t,
+				"goldsert: created missing golden file: "+file+"\n",
+				logs.String(),
+			)
+		})
+	}
+}
+
+func TestAssert_JSONMarshaling_CreateMissing(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goldsert-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	gs := New()
+	gs.Golden.Dirname = dir
+	gs.Golden.UpdateFunc = func() bool { return false }
+	gs.UpdateFilterFunc = nil
+	gs.ReviewFunc = nil
+	gs.CIFunc = nil
+	gs.CreateMissingFunc = func() bool { return true }
+	logs := captureLogs(gs)
+
+	file := gs.Golden.FileP(t, "goldsert_json")
+	require.NoFileExists(t, file)
+	want := "{\n  \"id\": \"1\",\n  \"title\": \"The Traveler\"\n}\n"
+
+	ok, output := runTest(t, func(t *testing.T) {
+		gs.JSONMarshaling(t, &Book{ID: "1", Title: "The Traveler"})
+	})
+
+	assert.True(t, ok, "missing golden file must be created:\n%s", output)
+	assert.Equal(t,
+		"goldsert: created missing golden file: "+file+"\n", logs.String(),
+	)
+	got, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, want, string(got))
+
+	logs.Reset()
+	ok, _ = runTest(t, func(t *testing.T) {
+		gs.JSONMarshaling(t, &Book{ID: "1", Title: "The Traveller"})
+	})
+
+	assert.False(t, ok, "existing golden file must be asserted")
+	assert.Empty(t, logs.String())
+	got, err = ioutil.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, want, string(got), "golden file must not be modified")
+}
+
+func TestAssert_createFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		gold        string
+		data        string
+		want        string
+		wantCreated bool
+	}{
+		{
+			name:        "missing golden file",
+			data:        "{\"id\":1}\n",
+			want:        "{\"id\":1}\n",
+			wantCreated: true,
+		},
+		{
+			name:        "existing golden file",
+			gold:        "{\"id\":1}\n",
+			data:        "{\"id\":2}\n",
+			want:        "{\"id\":1}\n",
+			wantCreated: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "goldsert-")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+
+			file := filepath.Join(dir, "TestBook", "goldsert_json.golden")
+			if tt.gold != "" {
+				require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+				err := ioutil.WriteFile(file, []byte(tt.gold), 0o644)
+				require.NoError(t, err)
+			}
+
+			gs := New()
+			created, err := gs.createFile(file, []byte(tt.data))
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantCreated, created)
+			got, err := ioutil.ReadFile(file)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestEnvCreateMissingFunc(t *testing.T) {
+	for env, want := range map[string]bool{
+		"":      false,
+		"0":     false,
+		"false": false,
+		"1":     true,
+		"on":    true,
+		"true":  true,
+	} {
+		t.Run(env, func(t *testing.T) {
+			setenv(t, map[string]string{"GOLDSERT_CREATE_MISSING": env})
+
+			assert.Equal(t, want, EnvCreateMissingFunc())
+		})
+	}
+}
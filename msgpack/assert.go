@@ -0,0 +1,229 @@
+package msgpack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jimeh/go-goldsert"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Assert holds both configuration and implementation of all MessagePack
+// assertion methods.
+//
+// You can customize serialization by setting the EncoderFunc and DecoderFunc
+// fields to a custom function that returns an encoder/decoder configured as
+// you need.
+//
+// Golden files are handled by the Goldsert field, whose Golden field configures
+// golden file generation, and whose NormalizeLineBreaks field enables
+// line-break normalization of JSON golden files. Raw MessagePack golden files
+// are never normalized. See the github.com/jimeh/go-goldsert package for
+// details about what can be configured.
+type Assert struct {
+	EncoderFunc func(io.Writer) *msgpack.Encoder
+	DecoderFunc func(io.Reader) *msgpack.Decoder
+	Goldsert    *goldsert.Assert
+}
+
+// New returns a new *Assert instance configured with default settings.
+//
+// The default encoder sorts map keys, ensuring golden files are stable. The
+// default decoder prohibits unknown fields which are not present on the
+// provided struct.
+func New() *Assert {
+	return &Assert{
+		EncoderFunc: newEncoder,
+		DecoderFunc: newDecoder,
+		Goldsert:    goldsert.New(),
+	}
+}
+
+// MsgpackMarshaling asserts that the given "v" value MessagePack marshals to an
+// expected value fetched from golden files on disk, and then verifies that the
+// marshaled result produces a value that is equal to "v" when unmarshaled.
+//
+// Used for objects that do NOT change when they are marshaled and unmarshaled.
+func (s *Assert) MsgpackMarshaling(t *testing.T, v interface{}) {
+	t.Helper()
+
+	s.MsgpackMarshalingP(t, v, v)
+}
+
+// MsgpackMarshalingP asserts that the given "v" value MessagePack marshals to
+// an expected value fetched from golden files on disk, and then verifies that
+// the marshaled result produces a value that is equal to "want" when
+// unmarshaled.
+//
+// Used for objects that change when they are marshaled and unmarshaled.
+func (s *Assert) MsgpackMarshalingP(t *testing.T, v, want interface{}) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	err := s.EncoderFunc(&buf).Encode(v)
+	require.NoErrorf(t, err, "failed to MessagePack marshal %T: %+v", v, v)
+
+	marshaled := buf.Bytes()
+	marshaledJSON, err := toJSON(marshaled)
+	require.NoErrorf(t, err,
+		"failed to convert MessagePack of %T to JSON: %+v", v, v,
+	)
+
+	gold := s.Goldsert.GoldenBinaryP(t,
+		"goldsert_msgpack", marshaled, msgpackEqual,
+	)
+	goldJSON := s.Goldsert.GoldenP(t,
+		"goldsert_msgpack_json", marshaledJSON, nil,
+	)
+
+	goldRawJSON, err := toJSON(gold)
+	require.NoErrorf(t, err,
+		"failed to convert MessagePack from %s to JSON",
+		s.Goldsert.Golden.FileP(t, "goldsert_msgpack"),
+	)
+	assert.Equalf(t, string(goldRawJSON), string(goldJSON),
+		"golden files %s and %s are out of sync",
+		s.Goldsert.Golden.FileP(t, "goldsert_msgpack"),
+		s.Goldsert.Golden.FileP(t, "goldsert_msgpack_json"),
+	)
+
+	msgpackEq(t, gold, marshaled)
+	assert.Equal(t, string(goldJSON), string(marshaledJSON))
+
+	if reflect.ValueOf(want).Kind() != reflect.Ptr {
+		require.FailNowf(t,
+			"only pointer types can be asserted",
+			"%T is not a pointer type", want,
+		)
+	}
+
+	got := reflect.New(reflect.TypeOf(want).Elem()).Interface()
+	err = s.DecoderFunc(bytes.NewBuffer(gold)).Decode(got)
+	require.NoErrorf(t, err,
+		"failed to MessagePack unmarshal %T from %s",
+		got, s.Goldsert.Golden.FileP(t, "goldsert_msgpack"),
+	)
+	assert.Equal(t, want, got,
+		"unmarshaling from golden file does not match expected object",
+	)
+}
+
+// msgpackEq asserts that two MessagePack values are semantically equal, by
+// decoding both into generic values and comparing them.
+func msgpackEq(t *testing.T, expected, actual []byte) {
+	t.Helper()
+
+	expectedMsgpack, err := decodeValue(expected)
+	require.NoErrorf(t, err,
+		"Expected value ('%x') is not valid msgpack.\n"+
+			"MessagePack parsing error: '%s'",
+		expected, err,
+	)
+	actualMsgpack, err := decodeValue(actual)
+	require.NoErrorf(t, err,
+		"Input ('%x') needs to be valid msgpack.\n"+
+			"MessagePack parsing error: '%s'",
+		actual, err,
+	)
+
+	assert.Equal(t, expectedMsgpack, actualMsgpack)
+}
+
+// msgpackEqual returns true if both MessagePack values decode into equal
+// generic values.
+func msgpackEqual(gold, data []byte) bool {
+	a, err := decodeValue(gold)
+	if err != nil {
+		return false
+	}
+	b, err := decodeValue(data)
+	if err != nil {
+		return false
+	}
+
+	return assert.ObjectsAreEqual(a, b)
+}
+
+// decodeValue decodes the given MessagePack value into a generic value. Maps
+// are decoded as map[interface{}]interface{}, as keys may be of any type.
+func decodeValue(data []byte) (interface{}, error) {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetMapDecoder(func(d *msgpack.Decoder) (interface{}, error) {
+		return d.DecodeUntypedMap()
+	})
+
+	return dec.DecodeInterface()
+}
+
+// toJSON converts the given MessagePack value to indented JSON. Map keys which
+// are not strings are converted to strings, binary data is base64 encoded, and
+// timestamps are rendered in UTC.
+func toJSON(data []byte) ([]byte, error) {
+	v, err := decodeValue(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+
+	err = enc.Encode(jsonValue(v))
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// jsonValue recursively converts maps with non-string keys to maps with string
+// keys, so the value can be marshaled to JSON. Timestamps are converted to UTC,
+// as MessagePack decodes them in the local timezone.
+func jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case time.Time:
+		return v.UTC()
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			m[fmt.Sprint(key)] = jsonValue(value)
+		}
+
+		return m
+	case []interface{}:
+		s := make([]interface{}, 0, len(v))
+		for _, value := range v {
+			s = append(s, jsonValue(value))
+		}
+
+		return s
+	default:
+		return v
+	}
+}
+
+// newEncoder is the default EncoderFunc used by Assert. It returns a
+// *msgpack.Encoder which sorts map keys.
+func newEncoder(w io.Writer) *msgpack.Encoder {
+	enc := msgpack.NewEncoder(w)
+	enc.SetSortMapKeys(true)
+
+	return enc
+}
+
+// newDecoder is the default DecoderFunc used by Assert. It returns a
+// *msgpack.Decoder which disallows unknown fields.
+func newDecoder(r io.Reader) *msgpack.Decoder {
+	dec := msgpack.NewDecoder(r)
+	dec.DisallowUnknownFields(true)
+
+	return dec
+}
@@ -0,0 +1,25 @@
+package msgpack
+
+import (
+	"testing"
+)
+
+func TestAssert_MsgpackMarshaling(t *testing.T) {
+	for _, tt := range marshalingTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+
+			gs.MsgpackMarshaling(t, tt.v)
+		})
+	}
+}
+
+func TestAssert_MsgpackMarshalingP(t *testing.T) {
+	for _, tt := range marshalingPTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+
+			gs.MsgpackMarshalingP(t, tt.v, tt.want)
+		})
+	}
+}
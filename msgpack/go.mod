@@ -0,0 +1,9 @@
+module github.com/jimeh/go-goldsert/msgpack
+
+go 1.15
+
+require (
+	github.com/jimeh/go-goldsert v0.2.0
+	github.com/stretchr/testify v1.7.0
+	github.com/vmihailenco/msgpack/v5 v5.3.5
+)
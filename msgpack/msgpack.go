@@ -0,0 +1,63 @@
+// Package msgpack provides goldsert test helpers for MessagePack, which use
+// golden files to assert marshaling and unmarshaling of given objects.
+//
+// It lives in its own module so that the core goldsert module does not depend
+// on a MessagePack library.
+//
+// As MessagePack is a binary format, each assertion uses two golden files; one
+// with the raw MessagePack bytes, and one with the same data converted to
+// human readable JSON, making changes easy to review.
+//
+// Each test helper operates in two stages:
+//
+// Firstly they marshal the provided object to MessagePack and reads the
+// corresponding golden files from disk, followed by verifying the raw bytes
+// are semantically identical, and that the JSON representation of both is
+// identical.
+//
+// Secondly they unmarshal the raw bytes from the golden file, and verifies that
+// the result is identical to the original object.
+//
+// Usage
+//
+//  func TestSessionMarshaling(t *testing.T) {
+//      msgpack.MsgpackMarshaling(t, &Session{UserID: 42})
+//  }
+//
+// The above example will read from the following golden files:
+//
+//  testdata/TestSessionMarshaling/goldsert_msgpack.golden
+//  testdata/TestSessionMarshaling/goldsert_msgpack_json.golden
+//
+// To create/update golden files, simply set the GOLDEN_UPDATE environment
+// variable to one of "1", "y", "t", "yes", "on", or "true" when running tests.
+package msgpack
+
+import (
+	"testing"
+)
+
+var global = New()
+
+// MsgpackMarshaling asserts that the given "v" value MessagePack marshals to an
+// expected value fetched from golden files on disk, and then verifies that the
+// marshaled result produces a value that is equal to "v" when unmarshaled.
+//
+// Used for objects that do NOT change when they are marshaled and unmarshaled.
+func MsgpackMarshaling(t *testing.T, v interface{}) {
+	t.Helper()
+
+	global.MsgpackMarshaling(t, v)
+}
+
+// MsgpackMarshalingP asserts that the given "v" value MessagePack marshals to
+// an expected value fetched from golden files on disk, and then verifies that
+// the marshaled result produces a value that is equal to "want" when
+// unmarshaled.
+//
+// Used for objects that change when they are marshaled and unmarshaled.
+func MsgpackMarshalingP(t *testing.T, v, want interface{}) {
+	t.Helper()
+
+	global.MsgpackMarshalingP(t, v, want)
+}
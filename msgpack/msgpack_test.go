@@ -0,0 +1,162 @@
+package msgpack
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+//
+// Helpers
+//
+
+type User struct {
+	ID   int64  `msgpack:"id"`
+	Name string `msgpack:"name"`
+}
+
+type Session struct {
+	Token     string            `msgpack:"token"`
+	User      *User             `msgpack:"user,omitempty"`
+	Scopes    []string          `msgpack:"scopes,omitempty"`
+	Data      []byte            `msgpack:"data,omitempty"`
+	Meta      map[string]string `msgpack:"meta,omitempty"`
+	Counters  map[int]int       `msgpack:"counters,omitempty"`
+	ExpiresAt *time.Time        `msgpack:"expires_at,omitempty"`
+
+	Hits  int `msgpack:"-"`
+	order int
+}
+
+// Point implements msgpack.CustomEncoder and msgpack.CustomDecoder to store
+// itself as a compact "x,y" string.
+type Point struct {
+	X int
+	Y int
+}
+
+var (
+	_ msgpack.CustomEncoder = (*Point)(nil)
+	_ msgpack.CustomDecoder = (*Point)(nil)
+)
+
+func (s *Point) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return enc.EncodeString(fmt.Sprintf("%d,%d", s.X, s.Y))
+}
+
+func (s *Point) DecodeMsgpack(dec *msgpack.Decoder) error {
+	str, err := dec.DecodeString()
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Sscanf(str, "%d,%d", &s.X, &s.Y)
+
+	return err
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+// sessionExpiresAt is in the local timezone, as MessagePack decodes timestamps
+// in the local timezone.
+var sessionExpiresAt = time.Date(
+	2021, time.October, 27, 22, 30, 34, 0, time.UTC,
+).Local()
+
+//
+// Test cases
+//
+
+var marshalingTestCases = []struct {
+	name string
+	v    interface{}
+}{
+	{
+		name: "true bool pointer",
+		v:    boolPtr(true),
+	},
+	{
+		name: "int pointer",
+		v:    intPtr(42),
+	},
+	{
+		name: "string pointer",
+		v:    stringPtr("hello world"),
+	},
+	{
+		name: "empty struct",
+		v:    &Session{},
+	},
+	{
+		name: "full struct",
+		v: &Session{
+			Token:    "2fd5af35",
+			User:     &User{ID: 42, Name: "Marty"},
+			Scopes:   []string{"read", "write"},
+			Data:     []byte{0x01, 0x02, 0xff},
+			Meta:     map[string]string{"ip": "127.0.0.1", "agent": "curl"},
+			Counters: map[int]int{1: 10, 2: 20},
+		},
+	},
+	{
+		name: "custom marshaling",
+		v:    &Point{X: 4, Y: 2},
+	},
+}
+
+var marshalingPTestCases = []struct {
+	name string
+	v    interface{}
+	want interface{}
+}{
+	{
+		name: "empty struct",
+		v:    &Session{},
+		want: &Session{},
+	},
+	{
+		name: "ignored fields",
+		v: &Session{
+			Token:     "2fd5af35",
+			ExpiresAt: &sessionExpiresAt,
+			Hits:      8,
+			order:     16,
+		},
+		want: &Session{
+			Token:     "2fd5af35",
+			ExpiresAt: &sessionExpiresAt,
+		},
+	},
+}
+
+//
+// Tests
+//
+
+func TestMsgpackMarshaling(t *testing.T) {
+	for _, tt := range marshalingTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			MsgpackMarshaling(t, tt.v)
+		})
+	}
+}
+
+func TestMsgpackMarshalingP(t *testing.T) {
+	for _, tt := range marshalingPTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			MsgpackMarshalingP(t, tt.v, tt.want)
+		})
+	}
+}
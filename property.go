@@ -0,0 +1,658 @@
+package goldsert
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var quickGeneratorType = reflect.TypeOf((*quick.Generator)(nil)).Elem()
+
+// maxShrinks is the maximum number of times a failing value is shrunk.
+const maxShrinks = 1000
+
+// propertyRunes are the runes random strings are composed of. They are all
+// printable, so strings survive a round-trip through all formats.
+var propertyRunes = []rune(
+	" !\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ" +
+		"[\\]^_`abcdefghijklmnopqrstuvwxyz{|}~éüßπ世界😀",
+)
+
+// GeneratorFunc returns a random value for RoundTripProperty. The size
+// argument is a hint for the length of strings, slices and maps.
+type GeneratorFunc func(r *rand.Rand, size int) interface{}
+
+// Generator configures the random values generated by RoundTripProperty.
+//
+// Values are generated by reflection, similar to testing/quick. Types which
+// implement quick.Generator generate their own values, as do types which have
+// a GeneratorFunc registered with SetFunc. Values of time.Time are generated
+// in UTC.
+//
+// Unexported struct fields, fields of type xml.Name, and fields which are
+// ignored by the format being tested (like `json:"-"`) are left zero.
+// Interface, channel and function values are always nil.
+type Generator struct {
+	// Type is the type of the values to generate. Pointer types generate
+	// non-nil pointers at the top-level.
+	Type reflect.Type
+
+	// Count is the number of values to generate. Defaults to 100.
+	Count int
+
+	// MaxSize is the maximum length of generated strings, slices and maps.
+	// Nested values get progressively smaller. Defaults to 10.
+	MaxSize int
+
+	// Seed seeds the random number generator. When zero, a time-based seed is
+	// used, which is logged if a failing value is found.
+	Seed int64
+
+	// Funcs holds custom GeneratorFuncs per type, which are used instead of
+	// reflection for values of each type, including nested values. Values
+	// from custom GeneratorFuncs are not shrunk. Use SetFunc to register a
+	// GeneratorFunc.
+	Funcs map[reflect.Type]GeneratorFunc
+
+	// Constraint, when set, is called with each generated and shrunk value,
+	// which is discarded if it returns false.
+	Constraint func(v interface{}) bool
+
+	// SaveFailing enables writing the minimal failing value, marshaled with
+	// the format being tested, to a golden file named
+	// roundtrip_failure/goldsert_<format>.golden within the golden file
+	// directory of the test, so it can be turned into a regular test case. In
+	// review mode, it is written to a pending golden file instead, and like
+	// updating golden files, writing it is refused in CI environments.
+	SaveFailing bool
+}
+
+// NewGenerator returns a new *Generator for values of the same type as the
+// given "v" value, configured with default settings.
+func NewGenerator(v interface{}) *Generator {
+	return &Generator{
+		Type:    reflect.TypeOf(v),
+		Count:   100,
+		MaxSize: 10,
+	}
+}
+
+// SetFunc registers the given GeneratorFunc for values of the same type as
+// "v". Pointer types are not dereferenced.
+func (g *Generator) SetFunc(v interface{}, fn GeneratorFunc) *Generator {
+	if g.Funcs == nil {
+		g.Funcs = map[reflect.Type]GeneratorFunc{}
+	}
+
+	g.Funcs[reflect.TypeOf(v)] = fn
+
+	return g
+}
+
+// RoundTripProperty asserts that random values generated by "gen" marshal and
+// unmarshal with the given format, producing a value equal to the generated
+// one.
+//
+// When a value fails the round-trip, it is shrunk to a minimal failing value
+// by repeatedly simplifying it, for example by removing slice elements and
+// map entries, shortening strings, and moving numbers towards zero. Only the
+// minimal failing value is reported. Golden files are not read, and are only
+// written when SaveFailing is enabled on the Generator.
+func (s *Assert) RoundTripProperty(
+	t *testing.T,
+	format Format,
+	gen *Generator,
+) {
+	t.Helper()
+
+	require.NotNil(t, gen.Type, "generator type cannot be nil")
+
+	seed := gen.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	pg := &propertyGen{
+		Generator: gen,
+		format:    format,
+		rand:      rand.New(rand.NewSource(seed)), //nolint:gosec
+	}
+
+	count := gen.Count
+	if count <= 0 {
+		count = 100
+	}
+
+	discarded := 0
+	for i := 0; i < count; {
+		v := pg.value(gen.Type, pg.maxSize(), true)
+		if !pg.accept(v) {
+			discarded++
+			require.Lessf(t, discarded, count*10,
+				"gave up after discarding %d generated %s values",
+				discarded, gen.Type,
+			)
+
+			continue
+		}
+		i++
+
+		if s.roundTripOK(format, v) {
+			continue
+		}
+
+		minimal, shrinks := s.shrink(pg, v)
+		s.reportRoundTrip(t, pg, minimal,
+			fmt.Sprintf("seed: %d, value: %d, shrinks: %d", seed, i, shrinks),
+		)
+
+		return
+	}
+}
+
+// reportRoundTrip fails the test with details of the given failing value,
+// and saves it as a golden file if enabled.
+func (s *Assert) reportRoundTrip(
+	t *testing.T,
+	pg *propertyGen,
+	v reflect.Value,
+	details string,
+) {
+	t.Helper()
+
+	got, data, err := s.roundTrip(pg.format, v)
+	msg := fmt.Sprintf("%s round-trip of %s failed (%s), minimal value:\n%s",
+		pg.format, v.Type(), details, dump(v.Interface(), false),
+	)
+
+	if err != nil {
+		assert.Fail(t, msg, err.Error())
+	} else {
+		assert.Equal(t, v.Interface(), got.Interface(),
+			msg+"\nmarshaled:\n"+string(data),
+		)
+	}
+
+	if pg.SaveFailing && data != nil {
+		s.saveFailing(t, pg.format, data)
+	}
+}
+
+// saveFailing writes the given marshaled failing value to the
+// roundtrip_failure golden file of the format, or to a pending golden file in
+// review mode. The test fails if the golden file is to be written in a CI
+// environment.
+func (s *Assert) saveFailing(t *testing.T, format Format, data []byte) {
+	t.Helper()
+
+	name := roundTripFailureDir + "/" + format.goldenName()
+	s.touch(t, name)
+	if s.ReviewFunc != nil && s.ReviewFunc() {
+		s.review(t, name, data, bytes.Equal, s.normalize)
+
+		return
+	}
+
+	s.refuseCIUpdate(t)
+	s.write(t, name, data, bytes.Equal, s.normalize)
+}
+
+// roundTrip marshals and unmarshals v with the given format, returning the
+// unmarshaled value and the marshaled data.
+func (s *Assert) roundTrip(
+	format Format,
+	v reflect.Value,
+) (reflect.Value, []byte, error) {
+	data, err := s.marshal(format, v.Interface())
+	if err != nil {
+		return reflect.Value{}, nil, fmt.Errorf(
+			"failed to %s marshal %s: %w", format, v.Type(), err,
+		)
+	}
+
+	typ := v.Type()
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	got := reflect.New(typ)
+	err = s.unmarshal(format, data, got.Interface())
+	if err != nil {
+		return reflect.Value{}, data, fmt.Errorf(
+			"failed to %s unmarshal %s from:\n%s\n%w",
+			format, v.Type(), data, err,
+		)
+	}
+
+	if v.Kind() != reflect.Ptr {
+		got = got.Elem()
+	}
+
+	return got, data, nil
+}
+
+// roundTripOK returns true if v survives a round-trip with the given format.
+func (s *Assert) roundTripOK(format Format, v reflect.Value) bool {
+	got, _, err := s.roundTrip(format, v)
+
+	return err == nil && assert.ObjectsAreEqual(v.Interface(), got.Interface())
+}
+
+// shrink returns the smallest failing value found by repeatedly replacing v
+// with the first of its shrink candidates which also fails, along with the
+// number of times it was shrunk.
+func (s *Assert) shrink(pg *propertyGen, v reflect.Value) (reflect.Value, int) {
+	shrinks := 0
+	for shrinks < maxShrinks {
+		shrunk := false
+		for _, c := range pg.shrink(v, true) {
+			if pg.accept(c) && !s.roundTripOK(pg.format, c) {
+				v = c
+				shrunk = true
+
+				break
+			}
+		}
+		if !shrunk {
+			break
+		}
+		shrinks++
+	}
+
+	return v, shrinks
+}
+
+// propertyGen generates and shrinks random values for a Generator.
+type propertyGen struct {
+	*Generator
+	format Format
+	rand   *rand.Rand
+}
+
+func (pg *propertyGen) maxSize() int {
+	if pg.MaxSize <= 0 {
+		return 10
+	}
+
+	return pg.MaxSize
+}
+
+func (pg *propertyGen) accept(v reflect.Value) bool {
+	return pg.Constraint == nil || pg.Constraint(v.Interface())
+}
+
+// custom returns true if values of typ are generated by a GeneratorFunc or
+// quick.Generator implementation rather than by reflection.
+func (pg *propertyGen) custom(typ reflect.Type) bool {
+	_, ok := pg.Funcs[typ]
+
+	return ok || typ.Implements(quickGeneratorType)
+}
+
+// value returns a random value of typ. Pointers are never nil when top is
+// true.
+func (pg *propertyGen) value(
+	typ reflect.Type,
+	size int,
+	top bool,
+) reflect.Value {
+	if fn, ok := pg.Funcs[typ]; ok {
+		return valueOf(fn(pg.rand, size), typ)
+	}
+	if typ.Implements(quickGeneratorType) {
+		g := reflect.Zero(typ).Interface().(quick.Generator)
+
+		return g.Generate(pg.rand, size)
+	}
+	if typ == timeType {
+		return reflect.ValueOf(time.Unix(
+			pg.rand.Int63n(1<<32), pg.rand.Int63n(int64(time.Second)),
+		).UTC())
+	}
+
+	v := reflect.New(typ).Elem()
+	switch typ.Kind() {
+	case reflect.Ptr:
+		if top || (size > 0 && pg.rand.Intn(size+1) > 0) {
+			p := reflect.New(typ.Elem())
+			p.Elem().Set(pg.value(typ.Elem(), size, false))
+			v.Set(p)
+		}
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			if pg.generated(typ.Field(i)) {
+				v.Field(i).Set(pg.value(typ.Field(i).Type, size-1, false))
+			}
+		}
+	case reflect.Slice:
+		if n := pg.length(size); n > 0 {
+			v.Set(reflect.MakeSlice(typ, n, n))
+			for i := 0; i < n; i++ {
+				v.Index(i).Set(pg.value(typ.Elem(), size-1, false))
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < typ.Len(); i++ {
+			v.Index(i).Set(pg.value(typ.Elem(), size-1, false))
+		}
+	case reflect.Map:
+		if n := pg.length(size); n > 0 {
+			v.Set(reflect.MakeMapWithSize(typ, n))
+			for i := 0; i < n; i++ {
+				v.SetMapIndex(
+					pg.value(typ.Key(), size-1, false),
+					pg.value(typ.Elem(), size-1, false),
+				)
+			}
+		}
+	case reflect.String:
+		runes := make([]rune, pg.length(size))
+		for i := range runes {
+			runes[i] = propertyRunes[pg.rand.Intn(len(propertyRunes))]
+		}
+		v.SetString(string(runes))
+	default:
+		pg.scalar(v, size)
+	}
+
+	return v
+}
+
+// scalar sets v to a random boolean or number. Numbers are small about half
+// of the time, and span the full range of their type otherwise.
+func (pg *propertyGen) scalar(v reflect.Value, size int) {
+	small := pg.rand.Intn(2) == 0
+	if size < 1 {
+		size = 1
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(pg.rand.Intn(2) == 1)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		if small {
+			v.SetInt(int64(pg.rand.Intn(2*size+1) - size))
+		} else {
+			v.SetInt(int64(pg.rand.Uint64()) >> (64 - v.Type().Bits()))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		if small {
+			v.SetUint(uint64(pg.rand.Intn(size + 1)))
+		} else {
+			v.SetUint(pg.rand.Uint64() >> (64 - v.Type().Bits()))
+		}
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(pg.float(small, v.Type().Bits()))
+	case reflect.Complex64, reflect.Complex128:
+		bits := v.Type().Bits() / 2
+		v.SetComplex(complex(pg.float(small, bits), pg.float(small, bits)))
+	default:
+		// Interface, channel, function and unsafe pointer values are left nil.
+	}
+}
+
+func (pg *propertyGen) float(small bool, bits int) float64 {
+	f := pg.rand.Float64()*2 - 1
+	if small {
+		f = math.Round(f * 100)
+	} else {
+		f *= math.Pow(10, float64(pg.rand.Intn(21)-10))
+	}
+	if bits == 32 {
+		f = float64(float32(f))
+	}
+
+	return f
+}
+
+// length returns a random length for strings, slices and maps of the given
+// size.
+func (pg *propertyGen) length(size int) int {
+	if size <= 0 {
+		return 0
+	}
+
+	return pg.rand.Intn(size + 1)
+}
+
+// generated returns true if a value is generated for the given struct field.
+func (pg *propertyGen) generated(f reflect.StructField) bool {
+	return f.PkgPath == "" &&
+		f.Type != xmlNameType &&
+		f.Tag.Get(string(pg.format)) != "-"
+}
+
+// shrink returns simpler variants of v, simplest first. Pointers are never
+// shrunk to nil when top is true.
+func (pg *propertyGen) shrink(v reflect.Value, top bool) []reflect.Value {
+	typ := v.Type()
+	if pg.custom(typ) {
+		return nil
+	}
+	if typ == timeType {
+		return shrinkTime(v.Interface().(time.Time))
+	}
+
+	switch typ.Kind() {
+	case reflect.Ptr:
+		return pg.shrinkPtr(v, top)
+	case reflect.Struct:
+		return pg.shrinkStruct(v)
+	case reflect.Slice:
+		return pg.shrinkSlice(v)
+	case reflect.Array:
+		return pg.shrinkArray(v)
+	case reflect.Map:
+		return pg.shrinkMap(v)
+	default:
+		return shrinkScalar(v)
+	}
+}
+
+func (pg *propertyGen) shrinkPtr(v reflect.Value, top bool) []reflect.Value {
+	if v.IsNil() {
+		return nil
+	}
+
+	var cs []reflect.Value
+	if !top {
+		cs = append(cs, reflect.Zero(v.Type()))
+	}
+	for _, c := range pg.shrink(v.Elem(), false) {
+		p := reflect.New(v.Type().Elem())
+		p.Elem().Set(c)
+		cs = append(cs, p)
+	}
+
+	return cs
+}
+
+func (pg *propertyGen) shrinkStruct(v reflect.Value) []reflect.Value {
+	var cs []reflect.Value
+	for i := 0; i < v.NumField(); i++ {
+		if !pg.generated(v.Type().Field(i)) {
+			continue
+		}
+		for _, c := range pg.shrink(v.Field(i), false) {
+			cv := reflect.New(v.Type()).Elem()
+			cv.Set(v)
+			cv.Field(i).Set(c)
+			cs = append(cs, cv)
+		}
+	}
+
+	return cs
+}
+
+func (pg *propertyGen) shrinkSlice(v reflect.Value) []reflect.Value {
+	n := v.Len()
+	if v.IsNil() {
+		return nil
+	}
+
+	cs := []reflect.Value{reflect.Zero(v.Type())}
+	if n > 2 {
+		cs = append(cs, v.Slice(0, n/2))
+	}
+	if n > 1 {
+		for i := 0; i < n; i++ {
+			cv := reflect.MakeSlice(v.Type(), 0, n-1)
+			cv = reflect.AppendSlice(cv, v.Slice(0, i))
+			cv = reflect.AppendSlice(cv, v.Slice(i+1, n))
+			cs = append(cs, cv)
+		}
+	}
+	for i := 0; i < n; i++ {
+		for _, c := range pg.shrink(v.Index(i), false) {
+			cv := reflect.MakeSlice(v.Type(), n, n)
+			reflect.Copy(cv, v)
+			cv.Index(i).Set(c)
+			cs = append(cs, cv)
+		}
+	}
+
+	return cs
+}
+
+func (pg *propertyGen) shrinkArray(v reflect.Value) []reflect.Value {
+	var cs []reflect.Value
+	for i := 0; i < v.Len(); i++ {
+		for _, c := range pg.shrink(v.Index(i), false) {
+			cv := reflect.New(v.Type()).Elem()
+			cv.Set(v)
+			cv.Index(i).Set(c)
+			cs = append(cs, cv)
+		}
+	}
+
+	return cs
+}
+
+func (pg *propertyGen) shrinkMap(v reflect.Value) []reflect.Value {
+	if v.IsNil() {
+		return nil
+	}
+
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+	})
+
+	cs := []reflect.Value{reflect.Zero(v.Type())}
+	if len(keys) > 1 {
+		for _, key := range keys {
+			cv := copyMap(v)
+			cv.SetMapIndex(key, reflect.Value{})
+			cs = append(cs, cv)
+		}
+	}
+	for _, key := range keys {
+		for _, c := range pg.shrink(v.MapIndex(key), false) {
+			cv := copyMap(v)
+			cv.SetMapIndex(key, c)
+			cs = append(cs, cv)
+		}
+	}
+
+	return cs
+}
+
+func copyMap(v reflect.Value) reflect.Value {
+	cv := reflect.MakeMapWithSize(v.Type(), v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		cv.SetMapIndex(iter.Key(), iter.Value())
+	}
+
+	return cv
+}
+
+func shrinkTime(tm time.Time) []reflect.Value {
+	var cs []reflect.Value
+	if !tm.IsZero() {
+		cs = append(cs, reflect.ValueOf(time.Time{}))
+	}
+	if s := tm.Truncate(time.Second); !s.Equal(tm) {
+		cs = append(cs, reflect.ValueOf(s))
+	}
+
+	return cs
+}
+
+// shrinkScalar returns simpler variants of strings, booleans and numbers,
+// moving them towards their zero value.
+func shrinkScalar(v reflect.Value) []reflect.Value {
+	if v.IsZero() {
+		return nil
+	}
+
+	var cs []reflect.Value
+	add := func(set func(reflect.Value)) {
+		cv := reflect.New(v.Type()).Elem()
+		set(cv)
+		cs = append(cs, cv)
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		runes := []rune(v.String())
+		add(func(cv reflect.Value) {})
+		if len(runes) > 2 {
+			add(func(cv reflect.Value) {
+				cv.SetString(string(runes[:len(runes)/2]))
+			})
+		}
+		if len(runes) > 1 {
+			add(func(cv reflect.Value) {
+				cv.SetString(string(runes[:len(runes)-1]))
+			})
+			add(func(cv reflect.Value) { cv.SetString(string(runes[1:])) })
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		i := v.Int()
+		add(func(cv reflect.Value) {})
+		if i/2 != 0 {
+			add(func(cv reflect.Value) { cv.SetInt(i / 2) })
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		u := v.Uint()
+		add(func(cv reflect.Value) {})
+		if u/2 != 0 {
+			add(func(cv reflect.Value) { cv.SetUint(u / 2) })
+		}
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		add(func(cv reflect.Value) {})
+		if tf := math.Trunc(f); tf != f && tf != 0 {
+			add(func(cv reflect.Value) { cv.SetFloat(tf) })
+		}
+	case reflect.Bool, reflect.Complex64, reflect.Complex128:
+		add(func(cv reflect.Value) {})
+	default:
+		return nil
+	}
+
+	return cs
+}
+
+// valueOf returns the reflect.Value of v, or the zero value of typ if v is
+// nil.
+func valueOf(v interface{}, typ reflect.Type) reflect.Value {
+	if v == nil {
+		return reflect.Zero(typ)
+	}
+
+	return reflect.ValueOf(v)
+}
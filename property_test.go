@@ -0,0 +1,248 @@
+package goldsert
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// truncatedString JSON marshals to at most 3 runes, so it does not survive a
+// round-trip when longer.
+type truncatedString string
+
+func (s truncatedString) MarshalJSON() ([]byte, error) {
+	r := []rune(string(s))
+	if len(r) > 3 {
+		r = r[:3]
+	}
+
+	return json.Marshal(string(r))
+}
+
+type lossyNote struct {
+	Title truncatedString `json:"title"`
+	Tags  []string        `json:"tags"`
+	Score int             `json:"score"`
+}
+
+type Temperature float64
+
+type Reading struct {
+	Sensor  string         `json:"sensor" yaml:"sensor" xml:"sensor"`
+	Celsius Temperature    `json:"celsius" yaml:"celsius" xml:"celsius"`
+	Taken   time.Time      `json:"taken" yaml:"taken" xml:"taken"`
+	Labels  map[string]int `json:"labels" yaml:"labels,omitempty" xml:"-"`
+}
+
+var roundTripPropertyTestCases = []struct {
+	name   string
+	format Format
+	gen    *Generator
+}{
+	{name: "json book", format: JSON, gen: NewGenerator(&Book{})},
+	{name: "yaml book", format: YAML, gen: NewGenerator(&Book{})},
+	{name: "xml book", format: XML, gen: NewGenerator(&Book{})},
+	{name: "json article", format: JSON, gen: NewGenerator(Article{})},
+	{name: "yaml article", format: YAML, gen: NewGenerator(&Article{})},
+	{name: "json reading", format: JSON, gen: NewGenerator(&Reading{})},
+	{name: "yaml reading", format: YAML, gen: NewGenerator(&Reading{})},
+	{name: "xml reading", format: XML, gen: NewGenerator(&Reading{})},
+	{name: "json map", format: JSON, gen: NewGenerator(map[string][]int{})},
+	{
+		name:   "json custom func and constraint",
+		format: JSON,
+		gen: func() *Generator {
+			g := NewGenerator(&Reading{})
+			g.SetFunc(Temperature(0),
+				func(r *rand.Rand, size int) interface{} {
+					return Temperature(r.Intn(100) - 40)
+				},
+			)
+			g.Constraint = func(v interface{}) bool {
+				return v.(*Reading).Sensor != ""
+			}
+
+			return g
+		}(),
+	},
+}
+
+func TestAssert_RoundTripProperty(t *testing.T) {
+	for _, tt := range roundTripPropertyTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+			gen := *tt.gen
+			gen.Seed = 42
+
+			gs.RoundTripProperty(t, tt.format, &gen)
+		})
+	}
+}
+
+func Test_propertyGen_value(t *testing.T) {
+	gen := NewGenerator(&Reading{})
+	gen.SetFunc(Temperature(0), func(r *rand.Rand, size int) interface{} {
+		return Temperature(21.5)
+	})
+	pg := &propertyGen{
+		Generator: gen,
+		format:    XML,
+		rand:      rand.New(rand.NewSource(1)),
+	}
+
+	for i := 0; i < 50; i++ {
+		v := pg.value(gen.Type, pg.maxSize(), true)
+		require.False(t, v.IsNil())
+
+		r := v.Interface().(*Reading)
+		assert.Equal(t, Temperature(21.5), r.Celsius)
+		assert.Nil(t, r.Labels, "xml:\"-\" field should not be generated")
+		assert.LessOrEqual(t, len([]rune(r.Sensor)), 10)
+		assert.Equal(t, time.UTC, r.Taken.Location())
+	}
+}
+
+func TestAssert_shrink(t *testing.T) {
+	gs := New()
+	pg := &propertyGen{
+		Generator: NewGenerator(&lossyNote{}),
+		format:    JSON,
+		rand:      rand.New(rand.NewSource(1)),
+	}
+
+	v := reflect.ValueOf(&lossyNote{
+		Title: "The Traveler",
+		Tags:  []string{"fiction", "thriller", "dystopia"},
+		Score: 9000,
+	})
+	require.False(t, gs.roundTripOK(JSON, v))
+
+	minimal, shrinks := gs.shrink(pg, v)
+
+	assert.Greater(t, shrinks, 0)
+	got := minimal.Interface().(*lossyNote)
+	assert.Len(t, []rune(string(got.Title)), 4)
+	assert.Contains(t, "The Traveler", string(got.Title))
+	assert.Nil(t, got.Tags)
+	assert.Equal(t, 0, got.Score)
+}
+
+func TestAssert_shrink_Constraint(t *testing.T) {
+	gs := New()
+	pg := &propertyGen{
+		Generator: NewGenerator(&lossyNote{}),
+		format:    JSON,
+		rand:      rand.New(rand.NewSource(1)),
+	}
+	pg.Constraint = func(v interface{}) bool {
+		return len(v.(*lossyNote).Tags) > 0
+	}
+
+	v := reflect.ValueOf(&lossyNote{
+		Title: "The Traveler",
+		Tags:  []string{"fiction", "thriller", "dystopia"},
+	})
+
+	minimal, _ := gs.shrink(pg, v)
+
+	got := minimal.Interface().(*lossyNote)
+	assert.Len(t, []rune(string(got.Title)), 4)
+	assert.Equal(t, []string{""}, got.Tags)
+}
+
+func Test_shrinkScalar(t *testing.T) {
+	tests := []struct {
+		name string
+		v    interface{}
+		want []interface{}
+	}{
+		{name: "zero int", v: 0, want: nil},
+		{name: "int", v: -9, want: []interface{}{0, -4}},
+		{name: "small int", v: 1, want: []interface{}{0}},
+		{
+			name: "uint8",
+			v:    uint8(200),
+			want: []interface{}{uint8(0), uint8(100)},
+		},
+		{name: "float", v: 3.75, want: []interface{}{0.0, 3.0}},
+		{name: "float below one", v: 0.5, want: []interface{}{0.0}},
+		{name: "bool", v: true, want: []interface{}{false}},
+		{
+			name: "string",
+			v:    "abcd",
+			want: []interface{}{"", "ab", "abc", "bcd"},
+		},
+		{name: "short string", v: "ü", want: []interface{}{""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []interface{}
+			for _, c := range shrinkScalar(reflect.ValueOf(tt.v)) {
+				got = append(got, c.Interface())
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAssert_RoundTripProperty_SaveFailing(t *testing.T) {
+	tests := []struct {
+		name        string
+		ci          bool
+		review      bool
+		wantFile    bool
+		wantPending bool
+		wantOut     string
+	}{
+		{name: "save", wantFile: true},
+		{name: "review", review: true, wantPending: true},
+		{
+			name:    "ci",
+			ci:      true,
+			wantOut: "refusing to update golden files in CI",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "goldsert-")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+			setenv(t, map[string]string{"GOLDSERT_ALLOW_CI_UPDATE": ""})
+
+			gs := New()
+			gs.Golden.Dirname = dir
+			gs.CIFunc = func() bool { return tt.ci }
+			gs.ReviewFunc = func() bool { return tt.review }
+			captureLogs(gs)
+			gen := NewGenerator(&lossyNote{})
+			gen.Seed = 42
+			gen.SaveFailing = true
+			file := gs.Golden.FileP(t, "roundtrip_failure/goldsert_json")
+
+			ok, output := runTest(t, func(t *testing.T) {
+				gs.RoundTripProperty(t, JSON, gen)
+			})
+
+			assert.False(t, ok, "failing round-trip must fail the test")
+			assert.Contains(t, output, tt.wantOut)
+			if tt.wantFile {
+				assert.FileExists(t, file)
+			} else {
+				assert.NoFileExists(t, file)
+			}
+			if tt.wantPending {
+				assert.FileExists(t, file+pendingSuffix)
+			} else {
+				assert.NoFileExists(t, file+pendingSuffix)
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package protobuf
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/jimeh/go-goldsert"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+)
+
+// Assert holds both configuration and implementation of all protobuf
+// assertion methods.
+//
+// You can customize serialization by modifying any of the marshal/unmarshal
+// options fields.
+//
+// Golden files are handled by the Goldsert field, whose Golden field configures
+// golden file generation, and whose NormalizeLineBreaks field enables
+// line-break normalization of marshaled messages and golden files. See the
+// github.com/jimeh/go-goldsert package for details about what can be
+// configured.
+type Assert struct {
+	JSONMarshalOptions   protojson.MarshalOptions
+	JSONUnmarshalOptions protojson.UnmarshalOptions
+	TextMarshalOptions   prototext.MarshalOptions
+	TextUnmarshalOptions prototext.UnmarshalOptions
+	Goldsert             *goldsert.Assert
+}
+
+// New returns a new *Assert instance configured with default settings.
+//
+// The default marshal options produce multi-line output indented with two
+// spaces, using the original proto field names in JSON. The default unmarshal
+// options reject unknown fields.
+func New() *Assert {
+	return &Assert{
+		JSONMarshalOptions: protojson.MarshalOptions{
+			Multiline:     true,
+			Indent:        "  ",
+			UseProtoNames: true,
+		},
+		TextMarshalOptions: prototext.MarshalOptions{
+			Multiline: true,
+			Indent:    "  ",
+		},
+		Goldsert: goldsert.New(),
+	}
+}
+
+// ProtoJSONMarshaling asserts that the given "m" message marshals with
+// protojson to an expected value fetched from a golden file on disk, and then
+// verifies that the marshaled result produces a message that is equal to "m"
+// when unmarshaled.
+//
+// Used for messages that do NOT change when they are marshaled and
+// unmarshaled.
+func (s *Assert) ProtoJSONMarshaling(t *testing.T, m proto.Message) {
+	t.Helper()
+
+	s.ProtoJSONMarshalingP(t, m, m)
+}
+
+// ProtoJSONMarshalingP asserts that the given "m" message marshals with
+// protojson to an expected value fetched from a golden file on disk, and then
+// verifies that the marshaled result produces a message that is equal to
+// "want" when unmarshaled.
+//
+// Used for messages that change when they are marshaled and unmarshaled.
+func (s *Assert) ProtoJSONMarshalingP(t *testing.T, m, want proto.Message) {
+	t.Helper()
+
+	b, err := s.JSONMarshalOptions.Marshal(m)
+	require.NoErrorf(t, err, "failed to protojson marshal %T: %+v", m, m)
+
+	b, err = stableJSON(b, s.JSONMarshalOptions.Indent)
+	require.NoErrorf(t, err, "failed to protojson marshal %T: %+v", m, m)
+
+	marshaled := s.normalize(b)
+	gold := s.Goldsert.GoldenP(t, "goldsert_protojson", marshaled,
+		messageEqual(m, s.JSONUnmarshalOptions.Unmarshal),
+	)
+
+	goldMsg := m.ProtoReflect().New().Interface()
+	err = s.JSONUnmarshalOptions.Unmarshal(gold, goldMsg)
+	require.NoErrorf(t, err,
+		"failed to protojson unmarshal %T from %s",
+		goldMsg, s.Goldsert.Golden.FileP(t, "goldsert_protojson"),
+	)
+	marshaledMsg := m.ProtoReflect().New().Interface()
+	err = s.JSONUnmarshalOptions.Unmarshal(marshaled, marshaledMsg)
+	require.NoErrorf(t, err, "failed to protojson unmarshal %T", marshaledMsg)
+	protoEqual(t, goldMsg, marshaledMsg,
+		"protojson marshaled message does not match golden file",
+	)
+
+	got := want.ProtoReflect().New().Interface()
+	err = s.JSONUnmarshalOptions.Unmarshal(gold, got)
+	require.NoErrorf(t, err,
+		"failed to protojson unmarshal %T from %s",
+		got, s.Goldsert.Golden.FileP(t, "goldsert_protojson"),
+	)
+	protoEqual(t, want, got,
+		"unmarshaling from golden file does not match expected message",
+	)
+}
+
+// ProtoTextMarshaling asserts that the given "m" message marshals with
+// prototext to an expected value fetched from a golden file on disk, and then
+// verifies that the marshaled result produces a message that is equal to "m"
+// when unmarshaled.
+//
+// Used for messages that do NOT change when they are marshaled and
+// unmarshaled.
+func (s *Assert) ProtoTextMarshaling(t *testing.T, m proto.Message) {
+	t.Helper()
+
+	s.ProtoTextMarshalingP(t, m, m)
+}
+
+// ProtoTextMarshalingP asserts that the given "m" message marshals with
+// prototext to an expected value fetched from a golden file on disk, and then
+// verifies that the marshaled result produces a message that is equal to
+// "want" when unmarshaled.
+//
+// Used for messages that change when they are marshaled and unmarshaled.
+func (s *Assert) ProtoTextMarshalingP(t *testing.T, m, want proto.Message) {
+	t.Helper()
+
+	b, err := s.TextMarshalOptions.Marshal(m)
+	require.NoErrorf(t, err, "failed to prototext marshal %T: %+v", m, m)
+
+	marshaled := s.normalize(stableText(b, s.TextMarshalOptions.Multiline))
+	gold := s.Goldsert.GoldenP(t, "goldsert_prototext", marshaled,
+		messageEqual(m, s.TextUnmarshalOptions.Unmarshal),
+	)
+
+	goldMsg := m.ProtoReflect().New().Interface()
+	err = s.TextUnmarshalOptions.Unmarshal(gold, goldMsg)
+	require.NoErrorf(t, err,
+		"failed to prototext unmarshal %T from %s",
+		goldMsg, s.Goldsert.Golden.FileP(t, "goldsert_prototext"),
+	)
+	marshaledMsg := m.ProtoReflect().New().Interface()
+	err = s.TextUnmarshalOptions.Unmarshal(marshaled, marshaledMsg)
+	require.NoErrorf(t, err, "failed to prototext unmarshal %T", marshaledMsg)
+	protoEqual(t, goldMsg, marshaledMsg,
+		"prototext marshaled message does not match golden file",
+	)
+
+	got := want.ProtoReflect().New().Interface()
+	err = s.TextUnmarshalOptions.Unmarshal(gold, got)
+	require.NoErrorf(t, err,
+		"failed to prototext unmarshal %T from %s",
+		got, s.Goldsert.Golden.FileP(t, "goldsert_prototext"),
+	)
+	protoEqual(t, want, got,
+		"unmarshaling from golden file does not match expected message",
+	)
+}
+
+// protoEqual asserts that both messages are equal according to proto.Equal. On
+// failure, a diff of the prototext representation of both messages is shown.
+func protoEqual(t *testing.T, expected, actual proto.Message, msg string) {
+	t.Helper()
+
+	if proto.Equal(expected, actual) {
+		return
+	}
+
+	opts := prototext.MarshalOptions{Multiline: true, Indent: "  "}
+	if !assert.Equal(t,
+		string(stableText([]byte(opts.Format(expected)), true)),
+		string(stableText([]byte(opts.Format(actual)), true)),
+		msg,
+	) {
+		return
+	}
+
+	assert.Fail(t, "messages are not equal according to proto.Equal", msg)
+}
+
+// messageEqual returns a function which reports if two marshaled messages of
+// the type of m are equal according to proto.Equal, after unmarshaling both
+// with unmarshal.
+func messageEqual(
+	m proto.Message,
+	unmarshal func([]byte, proto.Message) error,
+) func(gold, data []byte) bool {
+	return func(gold, data []byte) bool {
+		a := m.ProtoReflect().New().Interface()
+		b := m.ProtoReflect().New().Interface()
+		if unmarshal(gold, a) != nil || unmarshal(data, b) != nil {
+			return false
+		}
+
+		return proto.Equal(a, b)
+	}
+}
+
+// normalize returns data with line breaks normalized if the NormalizeLineBreaks
+// field of Goldsert is enabled.
+func (s *Assert) normalize(data []byte) []byte {
+	if s.Goldsert.NormalizeLineBreaks {
+		return normalizeLineBreaks(data)
+	}
+
+	return data
+}
+
+// stableJSON re-formats protojson output with encoding/json, removing the
+// randomly injected whitespace of protojson. Output is indented with indent
+// when it is not empty, and compacted otherwise.
+func stableJSON(data []byte, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	err := json.Compact(&buf, data)
+	if err != nil || indent == "" {
+		return buf.Bytes(), err
+	}
+
+	var out bytes.Buffer
+	err = json.Indent(&out, buf.Bytes(), "", indent)
+	if err != nil {
+		return nil, err
+	}
+	out.WriteString("\n")
+
+	return out.Bytes(), nil
+}
+
+var textFieldNameSpace = regexp.MustCompile(`(?m)^(\s*[^\s:"]+:) +`)
+
+// stableText removes the randomly injected whitespace of multi-line prototext
+// output. Single-line output is returned as is.
+func stableText(data []byte, multiline bool) []byte {
+	if !multiline {
+		return data
+	}
+
+	return textFieldNameSpace.ReplaceAll(data, []byte("$1 "))
+}
+
+func normalizeLineBreaks(data []byte) []byte {
+	// Replace CRLF (\r\n, windows) with LF (\n, unix)
+	result := bytes.ReplaceAll(data, []byte{13, 10}, []byte{10})
+	// Replace CR (\r, mac) with LF (\n, unix)
+	result = bytes.ReplaceAll(result, []byte{13}, []byte{10})
+
+	return result
+}
@@ -0,0 +1,161 @@
+package protobuf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestAssert_ProtoJSONMarshaling(t *testing.T) {
+	for _, tt := range marshalingTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+
+			gs.ProtoJSONMarshaling(t, tt.m)
+		})
+	}
+}
+
+func TestAssert_ProtoJSONMarshalingP(t *testing.T) {
+	for _, tt := range marshalingPTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+
+			gs.ProtoJSONMarshalingP(t, tt.m, tt.want)
+		})
+	}
+}
+
+func TestAssert_ProtoTextMarshaling(t *testing.T) {
+	for _, tt := range marshalingTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+
+			gs.ProtoTextMarshaling(t, tt.m)
+		})
+	}
+}
+
+func TestAssert_ProtoTextMarshalingP(t *testing.T) {
+	for _, tt := range marshalingPTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+
+			gs.ProtoTextMarshalingP(t, tt.m, tt.want)
+		})
+	}
+}
+
+func Test_stableJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		indent string
+		want   string
+	}{
+		{
+			name:   "extra spaces",
+			data:   "{\n  \"a\":  1,\n  \"b\":  [\n    \"x  y\"\n  ]\n}",
+			indent: "  ",
+			want:   "{\n  \"a\": 1,\n  \"b\": [\n    \"x  y\"\n  ]\n}\n",
+		},
+		{
+			name: "compact",
+			data: `{"a":1, "b":["x  y"]}`,
+			want: `{"a":1,"b":["x  y"]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := stableJSON([]byte(tt.data), tt.indent)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func Test_stableText(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		multiline bool
+		want      string
+	}{
+		{
+			name:      "multiline extra spaces",
+			data:      "a:  1\nb:  {\n  c:  \"x  y: z\"\n}\n[foo.bar]:  2\n",
+			multiline: true,
+			want:      "a: 1\nb: {\n  c: \"x  y: z\"\n}\n[foo.bar]: 2\n",
+		},
+		{
+			name: "single line",
+			data: `a: 1  b: "x  y: z"`,
+			want: `a: 1  b: "x  y: z"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := stableText([]byte(tt.data), tt.multiline)
+
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func Test_messageEqual(t *testing.T) {
+	tests := []struct {
+		name      string
+		unmarshal func([]byte, proto.Message) error
+		gold      string
+		data      string
+		want      bool
+	}{
+		{
+			name:      "protojson equal",
+			unmarshal: protojson.Unmarshal,
+			gold:      "\"x\"",
+			data:      "\"x\"\n",
+			want:      true,
+		},
+		{
+			name:      "protojson changed",
+			unmarshal: protojson.Unmarshal,
+			gold:      "\"x\"",
+			data:      "\"y\"",
+			want:      false,
+		},
+		{
+			name:      "protojson invalid golden file",
+			unmarshal: protojson.Unmarshal,
+			gold:      "{",
+			data:      "\"x\"",
+			want:      false,
+		},
+		{
+			name:      "prototext equal",
+			unmarshal: prototext.Unmarshal,
+			gold:      "value:  \"x\"",
+			data:      "value: \"x\"\n",
+			want:      true,
+		},
+		{
+			name:      "prototext changed",
+			unmarshal: prototext.Unmarshal,
+			gold:      "value: \"x\"",
+			data:      "value: \"y\"",
+			want:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			equal := messageEqual(wrapperspb.String(""), tt.unmarshal)
+
+			assert.Equal(t, tt.want, equal([]byte(tt.gold), []byte(tt.data)))
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Package protobuf provides goldsert test helpers for Protocol Buffers
+// messages, which use golden files to assert marshaling and unmarshaling of
+// given messages in the protojson and prototext formats.
+//
+// It lives in its own module so that the core goldsert module does not depend
+// on the protobuf library.
+//
+// Each test helper operates in two stages:
+//
+// Firstly they marshal the provided message and reads the corresponding golden
+// file from disk, followed by verifying both are semantically identical.
+//
+// Secondly they unmarshal the content from the golden file, and verifies with
+// proto.Equal that the result is identical to the original message.
+//
+// The protojson and prototext packages deliberately produce unstable
+// whitespace in their output. Marshaled output is normalized before being
+// written to golden files, to keep them stable across builds.
+//
+// Usage
+//
+//  func TestUserMarshaling(t *testing.T) {
+//      msg := &pb.User{Name: "Hello World!"}
+//
+//      protobuf.ProtoJSONMarshaling(t, msg)
+//      protobuf.ProtoTextMarshaling(t, msg)
+//  }
+//
+// The above example will read from the following golden files:
+//
+//  testdata/TestUserMarshaling/goldsert_protojson.golden
+//  testdata/TestUserMarshaling/goldsert_prototext.golden
+//
+// To create/update golden files, simply set the GOLDEN_UPDATE environment
+// variable to one of "1", "y", "t", "yes", "on", or "true" when running tests.
+package protobuf
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+var global = New()
+
+// ProtoJSONMarshaling asserts that the given "m" message marshals with
+// protojson to an expected value fetched from a golden file on disk, and then
+// verifies that the marshaled result produces a message that is equal to "m"
+// when unmarshaled.
+//
+// Used for messages that do NOT change when they are marshaled and
+// unmarshaled.
+func ProtoJSONMarshaling(t *testing.T, m proto.Message) {
+	t.Helper()
+
+	global.ProtoJSONMarshaling(t, m)
+}
+
+// ProtoJSONMarshalingP asserts that the given "m" message marshals with
+// protojson to an expected value fetched from a golden file on disk, and then
+// verifies that the marshaled result produces a message that is equal to
+// "want" when unmarshaled.
+//
+// Used for messages that change when they are marshaled and unmarshaled.
+func ProtoJSONMarshalingP(t *testing.T, m, want proto.Message) {
+	t.Helper()
+
+	global.ProtoJSONMarshalingP(t, m, want)
+}
+
+// ProtoTextMarshaling asserts that the given "m" message marshals with
+// prototext to an expected value fetched from a golden file on disk, and then
+// verifies that the marshaled result produces a message that is equal to "m"
+// when unmarshaled.
+//
+// Used for messages that do NOT change when they are marshaled and
+// unmarshaled.
+func ProtoTextMarshaling(t *testing.T, m proto.Message) {
+	t.Helper()
+
+	global.ProtoTextMarshaling(t, m)
+}
+
+// ProtoTextMarshalingP asserts that the given "m" message marshals with
+// prototext to an expected value fetched from a golden file on disk, and then
+// verifies that the marshaled result produces a message that is equal to
+// "want" when unmarshaled.
+//
+// Used for messages that change when they are marshaled and unmarshaled.
+func ProtoTextMarshalingP(t *testing.T, m, want proto.Message) {
+	t.Helper()
+
+	global.ProtoTextMarshalingP(t, m, want)
+}
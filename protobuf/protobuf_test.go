@@ -0,0 +1,180 @@
+package protobuf
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+//
+// Helpers
+//
+
+func mustStruct(m map[string]interface{}) *structpb.Struct {
+	s, err := structpb.NewStruct(m)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+func mustAny(m proto.Message) *anypb.Any {
+	a, err := anypb.New(m)
+	if err != nil {
+		panic(err)
+	}
+
+	return a
+}
+
+func stringField(
+	name string,
+	number int32,
+	label descriptorpb.FieldDescriptorProto_Label,
+) *descriptorpb.FieldDescriptorProto {
+	return &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String(name),
+		Number:   proto.Int32(number),
+		Label:    label.Enum(),
+		Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+		JsonName: proto.String(name),
+	}
+}
+
+var fileDescriptor = &descriptorpb.FileDescriptorProto{
+	Name:    proto.String("library/v1/book.proto"),
+	Package: proto.String("library.v1"),
+	Syntax:  proto.String("proto3"),
+	MessageType: []*descriptorpb.DescriptorProto{
+		{
+			Name: proto.String("Book"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				stringField("id", 1,
+					descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL,
+				),
+				stringField("authors", 2,
+					descriptorpb.FieldDescriptorProto_LABEL_REPEATED,
+				),
+			},
+		},
+	},
+}
+
+//
+// Test cases
+//
+
+var marshalingTestCases = []struct {
+	name string
+	m    proto.Message
+}{
+	{
+		name: "empty message",
+		m:    &descriptorpb.FileDescriptorProto{},
+	},
+	{
+		name: "wrapper",
+		m:    wrapperspb.String("hello world"),
+	},
+	{
+		name: "timestamp",
+		m: timestamppb.New(
+			time.Date(2021, time.October, 27, 22, 30, 34, 0, time.UTC),
+		),
+	},
+	{
+		name: "duration",
+		m:    durationpb.New(90 * time.Minute),
+	},
+	{
+		name: "struct",
+		m: mustStruct(map[string]interface{}{
+			"title": "The Traveler",
+			"year":  2005,
+			"tags":  []interface{}{"fiction", "dystopia"},
+			"author": map[string]interface{}{
+				"first_name": "John",
+				"last_name":  "Twelve Hawks",
+			},
+		}),
+	},
+	{
+		name: "nested message",
+		m:    fileDescriptor,
+	},
+	{
+		name: "any",
+		m:    mustAny(wrapperspb.Int64(42)),
+	},
+}
+
+var marshalingPTestCases = []struct {
+	name string
+	m    proto.Message
+	want proto.Message
+}{
+	{
+		name: "empty message",
+		m:    &descriptorpb.FileDescriptorProto{},
+		want: &descriptorpb.FileDescriptorProto{},
+	},
+	{
+		name: "wrapper",
+		m:    wrapperspb.String("hello world"),
+		want: wrapperspb.String("hello world"),
+	},
+	{
+		name: "unknown fields",
+		m: func() proto.Message {
+			m := wrapperspb.String("hello world")
+			m.ProtoReflect().SetUnknown([]byte{0x10, 0x2a})
+
+			return m
+		}(),
+		want: wrapperspb.String("hello world"),
+	},
+}
+
+//
+// Tests
+//
+
+func TestProtoJSONMarshaling(t *testing.T) {
+	for _, tt := range marshalingTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ProtoJSONMarshaling(t, tt.m)
+		})
+	}
+}
+
+func TestProtoJSONMarshalingP(t *testing.T) {
+	for _, tt := range marshalingPTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ProtoJSONMarshalingP(t, tt.m, tt.want)
+		})
+	}
+}
+
+func TestProtoTextMarshaling(t *testing.T) {
+	for _, tt := range marshalingTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ProtoTextMarshaling(t, tt.m)
+		})
+	}
+}
+
+func TestProtoTextMarshalingP(t *testing.T) {
+	for _, tt := range marshalingPTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ProtoTextMarshalingP(t, tt.m, tt.want)
+		})
+	}
+}
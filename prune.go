@@ -0,0 +1,401 @@
+package goldsert
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// goldenPrefix is the prefix of the names of golden files written through
+// GoldenP, which are the only golden files considered by orphan detection.
+const goldenPrefix = "goldsert_"
+
+// roundTripFailureDir is the directory within the golden file directory of a
+// test where RoundTripProperty saves failing values. Golden files within it are
+// never considered orphaned, as they are not written by passing tests.
+const roundTripFailureDir = "roundtrip_failure"
+
+// Prune modes supported by the GOLDSERT_PRUNE environment variable.
+const (
+	pruneReport = "report"
+	pruneDelete = "delete"
+)
+
+var touched = newGoldenRegistry()
+
+// goldenRegistry records the golden files accessed by assertions.
+type goldenRegistry struct {
+	mu    sync.Mutex
+	files map[string]bool
+	dirs  map[string]string
+
+	// filtered reports if tests were filtered, in which case orphaned golden
+	// files are not looked for.
+	filtered func() bool
+
+	// short reports if tests run in short mode, in which case orphaned golden
+	// files are not deleted, as tests may have been skipped.
+	short func() bool
+
+	// reported is the path of the file listing the orphaned golden files found
+	// in report mode, which are the only ones deleted in delete mode.
+	reported string
+}
+
+func newGoldenRegistry() *goldenRegistry {
+	return &goldenRegistry{
+		files:    map[string]bool{},
+		dirs:     map[string]string{},
+		filtered: testsFiltered,
+		short:    testing.Short,
+		reported: reportedOrphansFile(),
+	}
+}
+
+// touch records the named golden file of the test as accessed.
+func (s *Assert) touch(t *testing.T, name string) {
+	t.Helper()
+
+	touched.add(s.Golden.Dirname, s.Golden.Suffix, s.Golden.FileP(t, name))
+}
+
+func (r *goldenRegistry) add(dirname, suffix, file string) {
+	dir, err := filepath.Abs(dirname)
+	if err != nil {
+		return
+	}
+	file, err = filepath.Abs(file)
+	if err != nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.dirs[dir] = suffix
+	r.files[file] = true
+}
+
+// orphans returns the paths of golden files written through GoldenP within
+// all golden file directories accessed, which have not been accessed
+// themselves. Golden file directories which no longer exist, like temporary
+// ones, and failing values saved by RoundTripProperty are ignored.
+func (r *goldenRegistry) orphans() ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var orphans []string
+	for dir, suffix := range r.dirs {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			continue
+		}
+
+		err := filepath.Walk(dir,
+			func(path string, info os.FileInfo, err error) error {
+				if err != nil {
+					return err
+				}
+				if info.IsDir() {
+					if info.Name() == roundTripFailureDir {
+						return filepath.SkipDir
+					}
+
+					return nil
+				}
+				name := info.Name()
+				if strings.HasPrefix(name, goldenPrefix) &&
+					strings.HasSuffix(name, suffix) && !r.files[path] {
+					orphans = append(orphans, path)
+				}
+
+				return nil
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(orphans)
+
+	return orphans, nil
+}
+
+// OrphanedGoldenFiles returns the paths of golden files written through
+// GoldenP, by the assertions of this package and of the additional format
+// modules, which have not been accessed by any assertion during the current
+// test run, within the golden file directories of all assertions which have
+// run. It is only meaningful once all tests of the package have run, and have
+// passed, as skipped and failed tests may not access all of their golden
+// files.
+//
+// Failing values saved by RoundTripProperty are never considered orphaned.
+func OrphanedGoldenFiles() ([]string, error) {
+	return touched.orphans()
+}
+
+// PruneGoldenFiles deletes all golden files returned by OrphanedGoldenFiles,
+// along with any directories left empty within the golden file directories,
+// returning the paths of the deleted golden files. Unlike Run in "delete"
+// mode, it does not check that the golden files were reported first, so it
+// must not be called when any test was skipped.
+func PruneGoldenFiles() ([]string, error) {
+	return touched.prune()
+}
+
+func (r *goldenRegistry) prune() ([]string, error) {
+	orphans, err := r.orphans()
+	if err != nil {
+		return nil, err
+	}
+
+	return orphans, r.remove(orphans)
+}
+
+// remove deletes the given golden files, along with any directories left
+// empty within the golden file directories.
+func (r *goldenRegistry) remove(paths []string) error {
+	for _, path := range paths {
+		err := os.Remove(path)
+		if err != nil {
+			return err
+		}
+		r.removeEmptyDirs(filepath.Dir(path))
+	}
+
+	return nil
+}
+
+// removeEmptyDirs removes dir and its parents while they are empty, stopping
+// at the golden file directory containing dir.
+func (r *goldenRegistry) removeEmptyDirs(dir string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for {
+		if _, ok := r.dirs[dir]; ok {
+			return
+		}
+		entries, err := ioutil.ReadDir(dir)
+		if err != nil || len(entries) > 0 || os.Remove(dir) != nil {
+			return
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return
+		}
+		dir = parent
+	}
+}
+
+// Run runs the tests of the package with m.Run, and then reports or deletes
+// orphaned golden files left behind by renamed or removed tests, as returned
+// by OrphanedGoldenFiles, when the GOLDSERT_PRUNE environment variable is set
+// to "report" or "delete". It returns the exit code to pass to os.Exit.
+//
+// When golden files were written in update mode, a summary of created, updated
+// and unchanged golden files is printed first, listing unchanged golden files
+// only when tests are run with the -v flag. Note that go test hides the output
+// of passing packages when given package arguments, like "go test ./...", so
+// the summary is then only shown with the -v flag, or when running "go test"
+// without arguments within the directory of a package.
+//
+// In "report" mode the exit code is non-zero if any orphaned golden files are
+// found. As tests skipped with t.Skip do not access their golden files, which
+// then appear orphaned, "delete" mode only deletes orphaned golden files which
+// were listed by a previous run in "report" mode, and refuses to run with the
+// -short flag. Orphaned golden files are only looked for when all tests
+// passed, and tests were not filtered with the -run or -skip flags.
+//
+// Usage:
+//
+//  func TestMain(m *testing.M) {
+//      os.Exit(goldsert.Run(m))
+//  }
+func Run(m *testing.M) int {
+	code := m.Run()
+	updated.report(os.Stderr, testing.Verbose())
+
+	return touched.run(os.Getenv("GOLDSERT_PRUNE"), code, os.Stderr)
+}
+
+// run reports or deletes orphaned golden files according to mode, given the
+// exit code of the test run, returning the exit code to use.
+func (r *goldenRegistry) run(mode string, code int, w io.Writer) int {
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	if mode == "" {
+		return code
+	}
+
+	switch {
+	case mode != pruneReport && mode != pruneDelete:
+		fmt.Fprintf(w, "goldsert: invalid GOLDSERT_PRUNE value %q, "+
+			"must be %q or %q\n", mode, pruneReport, pruneDelete,
+		)
+
+		return 1
+	case code != 0:
+		fmt.Fprintln(w, "goldsert: skipping orphaned golden file "+
+			"detection, as tests failed")
+
+		return code
+	case r.filtered():
+		fmt.Fprintln(w, "goldsert: skipping orphaned golden file "+
+			"detection, as tests were filtered with -run or -skip")
+
+		return code
+	}
+
+	if mode == pruneDelete {
+		return r.runDelete(code, w)
+	}
+
+	return r.runReport(code, w)
+}
+
+// runReport lists orphaned golden files, and records them in the reported
+// file for a later run in delete mode.
+func (r *goldenRegistry) runReport(code int, w io.Writer) int {
+	paths, err := r.orphans()
+	if err == nil {
+		err = writeLines(r.reported, paths)
+	}
+	if err != nil {
+		fmt.Fprintf(w, "goldsert: failed to report orphaned golden files: %s\n",
+			err,
+		)
+
+		return 1
+	}
+
+	wd, _ := os.Getwd()
+	for _, path := range paths {
+		fmt.Fprintf(w, "goldsert: orphaned golden file: %s\n",
+			relPath(wd, path),
+		)
+	}
+	if len(paths) > 0 {
+		return 1
+	}
+
+	return code
+}
+
+// runDelete deletes orphaned golden files which are listed in the reported
+// file, and lists remaining orphaned golden files without deleting them.
+func (r *goldenRegistry) runDelete(code int, w io.Writer) int {
+	if r.short() {
+		fmt.Fprintln(w, "goldsert: refusing to delete orphaned golden "+
+			"files, as tests may have been skipped with -short")
+
+		return 1
+	}
+
+	reported, err := readLines(r.reported)
+	if os.IsNotExist(err) {
+		fmt.Fprintf(w, "goldsert: refusing to delete orphaned golden "+
+			"files, as they were not reported, run tests with "+
+			"GOLDSERT_PRUNE=%s first\n", pruneReport,
+		)
+
+		return 1
+	}
+
+	var orphans, paths []string
+	if err == nil {
+		orphans, err = r.orphans()
+	}
+	if err == nil {
+		for _, path := range orphans {
+			if reported[path] {
+				paths = append(paths, path)
+			}
+		}
+		err = r.remove(paths)
+	}
+	if err == nil {
+		err = os.Remove(r.reported)
+	}
+	if err != nil {
+		fmt.Fprintf(w, "goldsert: failed to delete orphaned golden files: %s\n",
+			err,
+		)
+
+		return 1
+	}
+
+	wd, _ := os.Getwd()
+	for _, path := range orphans {
+		if reported[path] {
+			fmt.Fprintf(w, "goldsert: deleted orphaned golden file: %s\n",
+				relPath(wd, path),
+			)
+		} else {
+			fmt.Fprintf(w, "goldsert: kept orphaned golden file which was "+
+				"not reported: %s\n", relPath(wd, path),
+			)
+		}
+	}
+
+	return code
+}
+
+// reportedOrphansFile returns the path of the file listing the orphaned golden
+// files found in report mode, within the temporary directory, and specific to
+// the working directory, which is the directory of the package under test.
+func reportedOrphansFile() string {
+	wd, _ := os.Getwd()
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(wd))
+
+	return filepath.Join(os.TempDir(),
+		fmt.Sprintf("goldsert-orphans-%x", h.Sum64()),
+	)
+}
+
+// writeLines writes lines to the given file, one per line.
+func writeLines(file string, lines []string) error {
+	var buf bytes.Buffer
+	for _, line := range lines {
+		buf.WriteString(line + "\n")
+	}
+
+	return ioutil.WriteFile(file, buf.Bytes(), 0o644)
+}
+
+// readLines returns the lines of the given file as a set.
+func readLines(file string) (map[string]bool, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := map[string]bool{}
+	for _, line := range strings.Split(string(b), "\n") {
+		if line != "" {
+			lines[line] = true
+		}
+	}
+
+	return lines, nil
+}
+
+// testsFiltered returns true if the -run or -skip test flags are set.
+func testsFiltered() bool {
+	for _, name := range []string{"test.run", "test.skip"} {
+		if f := flag.Lookup(name); f != nil && f.Value.String() != "" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,201 @@
+package goldsert
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePruneTestFiles(t *testing.T, dir string, files []string) {
+	t.Helper()
+
+	for _, name := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, ioutil.WriteFile(path, []byte("{}\n"), 0o644))
+	}
+}
+
+func newPruneTestRegistry(t *testing.T) (*goldenRegistry, string) {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "goldsert-")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	dir, err = filepath.EvalSymlinks(dir)
+	require.NoError(t, err)
+
+	writePruneTestFiles(t, dir, []string{
+		"TestBook/full/goldsert_json.golden",
+		"TestBook/full/goldsert_yaml.golden",
+		"TestBook/empty/goldsert_json.golden",
+		"TestOld/case/goldsert_json.golden",
+		"TestOld/case/goldsert_toml.golden",
+		"TestOld/gone/goldsert_dump.golden",
+		"TestOld/gone/notes.txt",
+		"TestProperty/roundtrip_failure/goldsert_json.golden",
+		"TestRemoved/goldsert_xml.golden",
+	})
+
+	r := newGoldenRegistry()
+	r.filtered = func() bool { return false }
+	r.short = func() bool { return false }
+	r.reported = filepath.Join(dir, "reported")
+	for _, name := range []string{
+		"TestBook/full/goldsert_json.golden",
+		"TestBook/full/goldsert_yaml.golden",
+		"TestBook/empty/goldsert_json.golden",
+	} {
+		r.add(dir, ".golden", filepath.Join(dir, filepath.FromSlash(name)))
+	}
+
+	return r, dir
+}
+
+func Test_goldenRegistry_orphans(t *testing.T) {
+	r, dir := newPruneTestRegistry(t)
+	r.add(filepath.Join(dir, "removed"), ".golden",
+		filepath.Join(dir, "removed", "TestGone", "goldsert_json.golden"),
+	)
+
+	got, err := r.orphans()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		filepath.Join(dir, "TestOld", "case", "goldsert_json.golden"),
+		filepath.Join(dir, "TestOld", "case", "goldsert_toml.golden"),
+		filepath.Join(dir, "TestOld", "gone", "goldsert_dump.golden"),
+		filepath.Join(dir, "TestRemoved", "goldsert_xml.golden"),
+	}, got)
+}
+
+func Test_goldenRegistry_prune(t *testing.T) {
+	r, dir := newPruneTestRegistry(t)
+
+	got, err := r.prune()
+	require.NoError(t, err)
+	assert.Len(t, got, 4)
+
+	for _, path := range got {
+		assert.NoFileExists(t, path)
+	}
+	assert.FileExists(t, filepath.Join(dir,
+		"TestProperty", "roundtrip_failure", "goldsert_json.golden",
+	), "failing values saved by RoundTripProperty should be kept")
+	assert.FileExists(t, filepath.Join(dir, "TestOld/gone/notes.txt"))
+	assert.NoDirExists(t, filepath.Join(dir, "TestRemoved"))
+	assert.DirExists(t, dir)
+
+	got, err = r.orphans()
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func Test_goldenRegistry_run(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     string
+		code     int
+		short    bool
+		reported bool
+		want     int
+		wantOut  string
+		orphaned bool
+	}{
+		{name: "disabled", mode: "", code: 0, want: 0, orphaned: true},
+		{
+			name:    "invalid mode",
+			mode:    "yes",
+			want:    1,
+			wantOut: "invalid GOLDSERT_PRUNE value \"yes\"",
+		},
+		{
+			name:     "failed tests",
+			mode:     "delete",
+			code:     1,
+			want:     1,
+			wantOut:  "skipping orphaned golden file detection",
+			orphaned: true,
+		},
+		{
+			name:     "report",
+			mode:     "report",
+			want:     1,
+			wantOut:  "orphaned golden file: ",
+			orphaned: true,
+		},
+		{
+			name:     "delete",
+			mode:     "Delete",
+			reported: true,
+			want:     0,
+			wantOut:  "deleted orphaned golden file: ",
+		},
+		{
+			name:     "delete without report",
+			mode:     "delete",
+			want:     1,
+			wantOut:  "as they were not reported",
+			orphaned: true,
+		},
+		{
+			name:     "delete in short mode",
+			mode:     "delete",
+			short:    true,
+			reported: true,
+			want:     1,
+			wantOut:  "as tests may have been skipped with -short",
+			orphaned: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, dir := newPruneTestRegistry(t)
+			r.short = func() bool { return tt.short }
+			var buf bytes.Buffer
+			if tt.reported {
+				require.Equal(t, 1, r.run("report", 0, &buf))
+				buf.Reset()
+			}
+
+			got := r.run(tt.mode, tt.code, &buf)
+
+			assert.Equal(t, tt.want, got)
+			assert.Contains(t, buf.String(), tt.wantOut)
+
+			orphan := filepath.Join(dir, "TestRemoved", "goldsert_xml.golden")
+			if tt.orphaned || tt.mode == "yes" {
+				assert.FileExists(t, orphan)
+			} else {
+				assert.NoFileExists(t, orphan)
+			}
+		})
+	}
+}
+
+func Test_goldenRegistry_run_unreported(t *testing.T) {
+	r, dir := newPruneTestRegistry(t)
+	var buf bytes.Buffer
+	require.Equal(t, 1, r.run("report", 0, &buf))
+	added := filepath.Join(dir, "TestSkipped", "goldsert_json.golden")
+	writePruneTestFiles(t, dir, []string{"TestSkipped/goldsert_json.golden"})
+	buf.Reset()
+
+	got := r.run("delete", 0, &buf)
+
+	assert.Equal(t, 0, got)
+	assert.Contains(t, buf.String(),
+		"kept orphaned golden file which was not reported: ",
+	)
+	assert.FileExists(t, added)
+	assert.NoFileExists(t,
+		filepath.Join(dir, "TestRemoved", "goldsert_xml.golden"),
+	)
+	assert.NoFileExists(t, r.reported)
+}
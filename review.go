@@ -0,0 +1,96 @@
+package goldsert
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// pendingSuffix is appended to the path of a golden file to get the path of
+// its pending replacement written in review mode.
+const pendingSuffix = ".new"
+
+var truthyStrings = []string{"1", "y", "t", "yes", "on", "true"}
+
+// EnvReviewFunc checks if the GOLDSERT_REVIEW environment variable is set to
+// one of "1", "y", "t", "yes", "on", or "true".
+//
+// This is the default ReviewFunc of Assert.
+func EnvReviewFunc() bool {
+	return containsString(truthyStrings, os.Getenv("GOLDSERT_REVIEW"))
+}
+
+// review implements review mode for the named golden file, failing the test
+// when the golden file is missing or does not equal data. It returns the
+// content of the golden file, normalized with normalize, or data if it is
+// missing, so the remaining stages of the assertion can run.
+func (s *Assert) review(
+	t *testing.T,
+	name string,
+	data []byte,
+	equal func(gold, data []byte) bool,
+	normalize func([]byte) []byte,
+) []byte {
+	t.Helper()
+
+	file := s.Golden.FileP(t, name)
+	gold, exists, pending, err := s.reviewFile(file, data, equal, normalize)
+	switch {
+	case err != nil:
+		assert.Failf(t, "failed to review golden file", "%s: %s", file, err)
+		if !exists {
+			return data
+		}
+	case !exists:
+		assert.Failf(t, "golden file does not exist",
+			"%s is missing, wrote pending golden file for review: %s",
+			file, file+pendingSuffix,
+		)
+
+		return data
+	case pending:
+		s.log(t, "goldsert: wrote pending golden file for review: %s",
+			file+pendingSuffix,
+		)
+	}
+
+	return gold
+}
+
+// reviewFile writes data to a pending golden file next to the given golden
+// file, with a ".new" suffix, when the golden file is missing or does not
+// equal data. Otherwise any stale pending golden file is removed. It returns
+// the content of the golden file normalized with normalize, if it exists, and
+// true if a pending golden file was written.
+func (s *Assert) reviewFile(
+	file string,
+	data []byte,
+	equal func(gold, data []byte) bool,
+	normalize func([]byte) []byte,
+) (gold []byte, exists, pending bool, err error) {
+	gold, err = ioutil.ReadFile(file)
+	exists = err == nil
+	if exists {
+		gold = normalize(gold)
+		if equal(gold, data) {
+			err = os.Remove(file + pendingSuffix)
+			if os.IsNotExist(err) {
+				err = nil
+			}
+
+			return gold, true, false, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, false, false, err
+	}
+
+	err = os.MkdirAll(filepath.Dir(file), s.Golden.DirMode)
+	if err == nil {
+		err = ioutil.WriteFile(file+pendingSuffix, data, s.Golden.FileMode)
+	}
+
+	return gold, exists, err == nil, err
+}
@@ -0,0 +1,159 @@
+package goldsert
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssert_JSONMarshaling_Review(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goldsert-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	gs := New()
+	gs.Golden.Dirname = dir
+	gs.Golden.UpdateFunc = func() bool { return false }
+	gs.UpdateFilterFunc = nil
+	gs.CreateMissingFunc = nil
+	gs.ReviewFunc = func() bool { return true }
+	logs := captureLogs(gs)
+
+	file := gs.Golden.FileP(t, "goldsert_json")
+	pending := file + pendingSuffix
+	gold := "{\n  \"id\": \"1\",\n  \"title\": \"The Traveler\"\n}\n"
+	require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+	require.NoError(t, ioutil.WriteFile(file, []byte(gold), 0o644))
+	book := &Book{ID: "1", Title: "The Traveller"}
+
+	ok, output := runTest(t, func(t *testing.T) {
+		gs.JSONMarshaling(t, book)
+	})
+
+	assert.False(t, ok, "changed value must fail the test")
+	assert.Equal(t,
+		"goldsert: wrote pending golden file for review: "+pending+"\n",
+		logs.String(),
+	)
+	assert.Contains(t, output, `"title":"The Traveler"`)
+	got, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, gold, string(got), "golden file must not be modified")
+	got, err = ioutil.ReadFile(pending)
+	require.NoError(t, err)
+	assert.Equal(t,
+		"{\n  \"id\": \"1\",\n  \"title\": \"The Traveller\"\n}\n",
+		string(got),
+	)
+
+	require.NoError(t, os.Rename(pending, file))
+	ok, output = runTest(t, func(t *testing.T) {
+		gs.JSONMarshaling(t, book)
+	})
+
+	assert.True(t, ok, "accepted pending golden file must pass:\n%s", output)
+	assert.NoFileExists(t, pending)
+
+	require.NoError(t, os.Remove(file))
+	ok, output = runTest(t, func(t *testing.T) {
+		gs.JSONMarshaling(t, book)
+	})
+
+	assert.False(t, ok, "missing golden file must fail the test")
+	assert.Contains(t, output, "golden file does not exist")
+	assert.NoFileExists(t, file)
+	assert.FileExists(t, pending)
+}
+
+func TestAssert_reviewFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		gold        *string
+		stale       bool
+		data        string
+		wantGold    []byte
+		wantPending bool
+	}{
+		{
+			name:        "missing golden file",
+			data:        "{\"id\": 1}\n",
+			wantPending: true,
+		},
+		{
+			name:        "empty golden file",
+			gold:        stringPtr(""),
+			data:        "",
+			wantPending: false,
+		},
+		{
+			name:        "equal golden file",
+			gold:        stringPtr("{\"id\":1}\n"),
+			data:        "{\"id\": 1}\n",
+			wantGold:    []byte("{\"id\":1}\n"),
+			wantPending: false,
+		},
+		{
+			name:        "equal golden file with stale pending file",
+			gold:        stringPtr("{\"id\":1}\n"),
+			stale:       true,
+			data:        "{\"id\":1}\n",
+			wantGold:    []byte("{\"id\":1}\n"),
+			wantPending: false,
+		},
+		{
+			name:        "changed golden file",
+			gold:        stringPtr("{\"id\":1}\r\n"),
+			data:        "{\"id\":2}\n",
+			wantGold:    []byte("{\"id\":1}\n"),
+			wantPending: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "goldsert-")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+
+			file := filepath.Join(dir, "TestBook", "goldsert_json.golden")
+			pending := file + pendingSuffix
+			require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+			if tt.gold != nil {
+				err := ioutil.WriteFile(file, []byte(*tt.gold), 0o644)
+				require.NoError(t, err)
+			}
+			if tt.stale {
+				err := ioutil.WriteFile(pending, []byte("{}\n"), 0o644)
+				require.NoError(t, err)
+			}
+
+			gs := New()
+			gold, exists, written, err := gs.reviewFile(
+				file, []byte(tt.data), jsonLinesEqual, gs.normalize,
+			)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantGold, gold)
+			assert.Equal(t, tt.gold != nil, exists)
+			assert.Equal(t, tt.wantPending, written)
+			if tt.wantPending {
+				got, err := ioutil.ReadFile(pending)
+				require.NoError(t, err)
+				assert.Equal(t, tt.data, string(got))
+			} else {
+				assert.NoFileExists(t, pending)
+			}
+
+			if tt.gold != nil {
+				got, err := ioutil.ReadFile(file)
+				require.NoError(t, err)
+				assert.Equal(t, *tt.gold, string(got),
+					"golden file must not be modified",
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,434 @@
+package goldsert
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+var (
+	jsonMarshalerType        = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	jsonNumberType           = reflect.TypeOf(json.Number(""))
+	errUnsupportedSchemaType = errors.New("unsupported JSON Schema type")
+)
+
+// SchemaSnapshot asserts that the JSON Schema derived from the type of the
+// given "v" value matches an expected JSON Schema fetched from a golden file on
+// disk. Changes to the JSON representation of a type, like renamed, added or
+// removed fields, are revealed by the golden file even when no other test
+// covers them.
+//
+// The schema follows JSON Schema draft 2020-12, and is derived with the same
+// rules encoding/json uses to marshal values. Struct fields are named by their
+// json tags, and are required unless tagged with omitempty. Pointers, slices
+// and maps are nullable. Named struct types are placed under "$defs", allowing
+// recursive types, named like "models.Book", with a numeric suffix like
+// "models.Book_2" for distinct types of the same name. Structs do not allow
+// additional properties, matching the default JSON decoder which disallows
+// unknown fields.
+//
+// Types implementing json.Marshaler accept any value, and types implementing
+// encoding.TextMarshaler are strings. Values of time.Time are strings with a
+// "date-time" format.
+func (s *Assert) SchemaSnapshot(t *testing.T, v interface{}) {
+	t.Helper()
+
+	schema, err := generateJSONSchema(reflect.TypeOf(v))
+	require.NoErrorf(t, err, "failed to generate JSON Schema for %T", v)
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	err = enc.Encode(schema)
+	require.NoErrorf(t, err, "failed to JSON marshal JSON Schema for %T", v)
+
+	marshaled := s.normalize(buf.Bytes())
+	gold := s.GoldenP(t, "goldsert_schema", marshaled, jsonEqual)
+	assert.JSONEq(t, string(gold), string(marshaled))
+}
+
+// generateJSONSchema returns a JSON Schema document describing the JSON
+// representation of values of the given type.
+func generateJSONSchema(typ reflect.Type) (map[string]interface{}, error) {
+	if typ == nil {
+		return nil, fmt.Errorf("%w: nil", errUnsupportedSchemaType)
+	}
+
+	g := &jsonSchemaGenerator{
+		defs:  map[string]map[string]interface{}{},
+		names: map[reflect.Type]string{},
+	}
+	schema, err := g.schema(indirectType(typ))
+	if err != nil {
+		return nil, err
+	}
+
+	doc := map[string]interface{}{"$schema": jsonSchemaDraft}
+	for k, v := range schema {
+		doc[k] = v
+	}
+	if len(g.defs) > 0 {
+		doc["$defs"] = g.defs
+	}
+
+	return doc, nil
+}
+
+type jsonSchemaGenerator struct {
+	// defs holds the schemas of named struct types, keyed by definition name.
+	defs map[string]map[string]interface{}
+
+	// names holds the definition name of each named struct type in defs,
+	// which is the type name qualified by its package name, with a numeric
+	// suffix when types from different packages share the same name.
+	names map[reflect.Type]string
+}
+
+func (g *jsonSchemaGenerator) schema(
+	typ reflect.Type,
+) (map[string]interface{}, error) {
+	switch {
+	case typ.Kind() == reflect.Ptr:
+		return g.nullable(typ.Elem())
+	case typ == timeType:
+		return map[string]interface{}{
+			"type": "string", "format": "date-time",
+		}, nil
+	case typ == jsonNumberType:
+		return map[string]interface{}{"type": "number"}, nil
+	case typ.Implements(jsonMarshalerType) ||
+		reflect.PtrTo(typ).Implements(jsonMarshalerType):
+		return map[string]interface{}{}, nil
+	case typ.Implements(textMarshalerType) ||
+		reflect.PtrTo(typ).Implements(textMarshalerType):
+		return map[string]interface{}{"type": "string"}, nil
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		return map[string]interface{}{"type": "integer"}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return map[string]interface{}{"type": "integer", "minimum": 0}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}, nil
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}, nil
+	case reflect.Interface:
+		return map[string]interface{}{}, nil
+	case reflect.Slice:
+		if typ.Elem().Kind() == reflect.Uint8 &&
+			!reflect.PtrTo(typ.Elem()).Implements(jsonMarshalerType) &&
+			!reflect.PtrTo(typ.Elem()).Implements(textMarshalerType) {
+			return map[string]interface{}{
+				"type":            []string{"string", "null"},
+				"contentEncoding": "base64",
+			}, nil
+		}
+
+		return g.array(typ, []string{"array", "null"})
+	case reflect.Array:
+		schema, err := g.array(typ, "array")
+		if err != nil {
+			return nil, err
+		}
+		schema["minItems"] = typ.Len()
+		schema["maxItems"] = typ.Len()
+
+		return schema, nil
+	case reflect.Map:
+		return g.object(typ)
+	case reflect.Struct:
+		return g.structRef(typ)
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnsupportedSchemaType, typ)
+	}
+}
+
+// nullable returns the schema of the given type, also allowing null.
+func (g *jsonSchemaGenerator) nullable(
+	typ reflect.Type,
+) (map[string]interface{}, error) {
+	schema, err := g.schema(typ)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := schema["type"].(type) {
+	case string:
+		schema["type"] = []string{t, "null"}
+	case []string:
+		// Already nullable.
+	default:
+		if len(schema) == 0 {
+			return schema, nil
+		}
+		schema = map[string]interface{}{
+			"anyOf": []interface{}{
+				schema, map[string]interface{}{"type": "null"},
+			},
+		}
+	}
+
+	return schema, nil
+}
+
+func (g *jsonSchemaGenerator) array(
+	typ reflect.Type,
+	schemaType interface{},
+) (map[string]interface{}, error) {
+	items, err := g.schema(typ.Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{"type": schemaType, "items": items}, nil
+}
+
+func (g *jsonSchemaGenerator) object(
+	typ reflect.Type,
+) (map[string]interface{}, error) {
+	key := typ.Key()
+	switch key.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+	default:
+		if !reflect.PtrTo(key).Implements(textMarshalerType) {
+			return nil, fmt.Errorf(
+				"%w: %s has unsupported key type",
+				errUnsupportedSchemaType, typ,
+			)
+		}
+	}
+
+	values, err := g.schema(typ.Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"type":                 []string{"object", "null"},
+		"additionalProperties": values,
+	}, nil
+}
+
+// structRef returns a reference to the schema of the given struct type under
+// "$defs", generating the schema if needed. Anonymous struct types are inlined
+// instead.
+func (g *jsonSchemaGenerator) structRef(
+	typ reflect.Type,
+) (map[string]interface{}, error) {
+	if typ.Name() == "" {
+		return g.structSchema(typ)
+	}
+
+	if name, ok := g.names[typ]; ok {
+		return map[string]interface{}{"$ref": "#/$defs/" + name}, nil
+	}
+
+	name := typ.String()
+	for i := 2; g.defs[name] != nil; i++ {
+		name = fmt.Sprintf("%s_%d", typ.String(), i)
+	}
+	ref := map[string]interface{}{"$ref": "#/$defs/" + name}
+
+	// Register a placeholder before generating the schema, so recursive
+	// references to the type resolve.
+	g.names[typ] = name
+	g.defs[name] = map[string]interface{}{}
+	schema, err := g.structSchema(typ)
+	if err != nil {
+		return nil, err
+	}
+	g.defs[name] = schema
+
+	return ref, nil
+}
+
+func (g *jsonSchemaGenerator) structSchema(
+	typ reflect.Type,
+) (map[string]interface{}, error) {
+	properties := map[string]interface{}{}
+	required := []string{}
+
+	for _, f := range jsonFieldsOf(typ) {
+		var schema map[string]interface{}
+		var err error
+		if f.quoted {
+			schema = map[string]interface{}{"type": "string"}
+			if f.typ.Kind() == reflect.Ptr {
+				schema["type"] = []string{"string", "null"}
+			}
+		} else {
+			schema, err = g.schema(f.typ)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", typ, f.goName, err)
+			}
+		}
+
+		properties[f.name] = schema
+		if !f.omitEmpty {
+			required = append(required, f.name)
+		}
+	}
+	sort.Strings(required)
+
+	schema := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	return schema, nil
+}
+
+// jsonField describes a struct field as marshaled by encoding/json.
+type jsonField struct {
+	name      string
+	goName    string
+	typ       reflect.Type
+	depth     int
+	tagged    bool
+	omitEmpty bool
+	quoted    bool
+}
+
+// jsonFieldsOf returns the fields of the given struct type which are marshaled
+// by encoding/json, including fields promoted from embedded structs. Like
+// encoding/json, shallower fields take precedence over deeper fields with the
+// same name, and tagged fields take precedence over untagged fields at the
+// same depth. Conflicting fields are dropped.
+func jsonFieldsOf(typ reflect.Type) []jsonField {
+	var all []jsonField
+	collectJSONFields(typ, 0, map[reflect.Type]bool{}, &all)
+
+	byName := map[string][]jsonField{}
+	var names []string
+	for _, f := range all {
+		if _, ok := byName[f.name]; !ok {
+			names = append(names, f.name)
+		}
+		byName[f.name] = append(byName[f.name], f)
+	}
+
+	fields := make([]jsonField, 0, len(names))
+	for _, name := range names {
+		if f, ok := dominantJSONField(byName[name]); ok {
+			fields = append(fields, f)
+		}
+	}
+
+	return fields
+}
+
+func collectJSONFields(
+	typ reflect.Type,
+	depth int,
+	visited map[reflect.Type]bool,
+	fields *[]jsonField,
+) {
+	if visited[typ] {
+		return
+	}
+	visited[typ] = true
+	defer delete(visited, typ)
+
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		ft := indirectType(f.Type)
+		if f.Anonymous {
+			if f.PkgPath != "" && ft.Kind() != reflect.Struct {
+				continue
+			}
+		} else if f.PkgPath != "" {
+			continue
+		}
+
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts := tag, ""
+		if i := strings.Index(tag, ","); i >= 0 {
+			name, opts = tag[:i], tag[i+1:]
+		}
+
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			collectJSONFields(ft, depth+1, visited, fields)
+
+			continue
+		}
+
+		field := jsonField{
+			name:   name,
+			goName: f.Name,
+			typ:    f.Type,
+			depth:  depth,
+			tagged: name != "",
+		}
+		if name == "" {
+			field.name = f.Name
+		}
+		for _, opt := range strings.Split(opts, ",") {
+			switch opt {
+			case "omitempty", "omitzero":
+				field.omitEmpty = true
+			case "string":
+				field.quoted = isJSONQuotable(ft)
+			}
+		}
+
+		*fields = append(*fields, field)
+	}
+}
+
+func dominantJSONField(fields []jsonField) (jsonField, bool) {
+	sort.SliceStable(fields, func(i, j int) bool {
+		if fields[i].depth != fields[j].depth {
+			return fields[i].depth < fields[j].depth
+		}
+
+		return fields[i].tagged && !fields[j].tagged
+	})
+
+	if len(fields) > 1 && fields[0].depth == fields[1].depth &&
+		fields[0].tagged == fields[1].tagged {
+		return jsonField{}, false
+	}
+
+	return fields[0], true
+}
+
+// isJSONQuotable returns true if the "string" json tag option applies to values
+// of the given type.
+func isJSONQuotable(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr, reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
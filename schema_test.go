@@ -0,0 +1,143 @@
+package goldsert
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Category struct {
+	Name     string      `json:"name"`
+	Parent   *Category   `json:"parent,omitempty"`
+	Children []*Category `json:"children"`
+}
+
+type Timestamps struct {
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+type Product struct {
+	SKU        string                 `json:"sku"`
+	Name       string                 `json:"name,omitempty"`
+	Price      float64                `json:"price"`
+	Stock      uint                   `json:"stock,string"`
+	Dimensions [3]float32             `json:"dimensions"`
+	Tags       []string               `json:"tags,omitempty"`
+	Image      []byte                 `json:"image,omitempty"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	Ratings    map[int]int            `json:"ratings,omitempty"`
+	Category   *Category              `json:"category"`
+	Raw        json.RawMessage        `json:"raw,omitempty"`
+	Count      json.Number            `json:"count"`
+	Status     Priority               `json:"status"`
+	Anonymous  struct {
+		Enabled bool `json:"enabled"`
+	} `json:"anonymous"`
+	Untagged string
+	Internal string `json:"-"`
+	internal string
+
+	Timestamps
+}
+
+var schemaSnapshotTestCases = []struct {
+	name string
+	v    interface{}
+}{
+	{name: "string", v: "hello"},
+	{name: "int slice", v: []int{}},
+	{name: "time", v: time.Time{}},
+	{name: "struct", v: &Book{}},
+	{name: "struct value", v: Article{}},
+	{name: "recursive struct", v: &Category{}},
+	{name: "complex struct", v: &Product{}},
+	{name: "map of structs", v: map[string]*Author{}},
+}
+
+func TestAssert_SchemaSnapshot(t *testing.T) {
+	for _, tt := range schemaSnapshotTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+
+			gs.SchemaSnapshot(t, tt.v)
+		})
+	}
+}
+
+func Test_generateJSONSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       interface{}
+		wantErr string
+	}{
+		{
+			name:    "nil",
+			v:       nil,
+			wantErr: "unsupported JSON Schema type: nil",
+		},
+		{
+			name:    "channel",
+			v:       make(chan int),
+			wantErr: "unsupported JSON Schema type: chan int",
+		},
+		{
+			name: "function field",
+			v:    &struct{ Callback func() }{},
+			wantErr: "struct { Callback func() }.Callback: " +
+				"unsupported JSON Schema type: func()",
+		},
+		{
+			name: "struct map keys",
+			v:    map[Author]string{},
+			wantErr: "unsupported JSON Schema type: " +
+				"map[goldsert.Author]string has unsupported key type",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := generateJSONSchema(reflect.TypeOf(tt.v))
+
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+// packageAuthor refers to Author from within functions declaring another type
+// of the same name.
+type packageAuthor = Author
+
+func Test_generateJSONSchema_sameTypeNames(t *testing.T) {
+	type Author struct {
+		Name string `json:"name"`
+	}
+	v := struct {
+		Local   Author         `json:"local"`
+		Package *packageAuthor `json:"package"`
+		Authors []Author       `json:"authors"`
+	}{}
+
+	got, err := generateJSONSchema(reflect.TypeOf(v))
+	require.NoError(t, err)
+
+	props := got["properties"].(map[string]interface{})
+	assert.Equal(t,
+		map[string]interface{}{"$ref": "#/$defs/goldsert.Author"},
+		props["local"],
+	)
+	assert.Equal(t,
+		[]interface{}{
+			map[string]interface{}{"$ref": "#/$defs/goldsert.Author_2"},
+			map[string]interface{}{"type": "null"},
+		},
+		props["package"].(map[string]interface{})["anyOf"],
+	)
+	defs := got["$defs"].(map[string]map[string]interface{})
+	assert.Len(t, defs, 2)
+	assert.Contains(t, defs["goldsert.Author"]["properties"], "name")
+	assert.Contains(t, defs["goldsert.Author_2"]["properties"], "first_name")
+}
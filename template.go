@@ -0,0 +1,40 @@
+package goldsert
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Template is a template which can be executed against a data object. It is
+// implemented by both *text/template.Template and *html/template.Template.
+type Template interface {
+	Name() string
+	Execute(w io.Writer, data interface{}) error
+}
+
+// TemplateRendering asserts that executing the given "tmpl" template with
+// "data" renders the expected output fetched from a golden file on disk.
+//
+// Templates are rendered without a second stage, as rendered output cannot be
+// parsed back into data.
+func (s *Assert) TemplateRendering(
+	t *testing.T,
+	tmpl Template,
+	data interface{},
+) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, data)
+	require.NoErrorf(t, err,
+		"failed to execute template %q with %T: %+v", tmpl.Name(), data, data,
+	)
+
+	rendered := s.normalize(buf.Bytes())
+	gold := s.GoldenP(t, "goldsert_template", rendered, nil)
+	assert.Equal(t, string(gold), string(rendered))
+}
@@ -0,0 +1,80 @@
+package goldsert
+
+import (
+	htmltemplate "html/template"
+	"testing"
+	texttemplate "text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var templateTestCases = []struct {
+	name string
+	tmpl Template
+	data interface{}
+}{
+	{
+		name: "text template",
+		tmpl: texttemplate.Must(texttemplate.New("email").Parse(
+			"Hi {{ .Author.FirstName }},\n\n" +
+				"Your book \"{{ .Title }}\" ({{ .Year }}) " +
+				"has been published.\n" +
+				"{{- if .Author.LastName }}\n\n" +
+				"By {{ .Author.LastName }}{{ end }}\n",
+		)),
+		data: &Book{
+			ID:     "cfda163c-d5c1-44a2-909b-5d2ce3a31979",
+			Title:  "The Traveler",
+			Author: &Author{FirstName: "John", LastName: "Twelve Hawks"},
+			Year:   2005,
+		},
+	},
+	{
+		name: "text template with map data",
+		tmpl: texttemplate.Must(texttemplate.New("config").Parse(
+			"{{ range $k, $v := . }}{{ $k }} = {{ $v }}\n{{ end }}",
+		)),
+		data: map[string]string{"listen": ":8080", "env": "production"},
+	},
+	{
+		name: "html template",
+		tmpl: htmltemplate.Must(htmltemplate.New("page").Parse(
+			"<h1>{{ .Title }}</h1>\n" +
+				"<a href=\"/books?id={{ .ID }}\">{{ .Author.FirstName }}</a>\n",
+		)),
+		data: &Book{
+			ID:     "a&b",
+			Title:  "<script>alert(1)</script>",
+			Author: &Author{FirstName: "Jane & John"},
+		},
+	},
+}
+
+func TestAssert_TemplateRendering(t *testing.T) {
+	for _, tt := range templateTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+
+			gs.TemplateRendering(t, tt.tmpl, tt.data)
+		})
+	}
+}
+
+func TestAssert_TemplateRendering_ExecutionError(t *testing.T) {
+	tmpl := texttemplate.Must(texttemplate.New("email").Parse(
+		"Hi {{ .Author.Nickname }},\n",
+	))
+
+	ok, output := runTest(t, func(t *testing.T) {
+		gs := New()
+		gs.TemplateRendering(t, tmpl, &Book{Author: &Author{}})
+	})
+
+	assert.False(t, ok)
+	assert.Contains(t, output,
+		"failed to execute template \"email\" with *goldsert.Book",
+	)
+	assert.Contains(t, output,
+		"can't evaluate field Nickname in type *goldsert.Author",
+	)
+}
@@ -0,0 +1,163 @@
+package toml
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/jimeh/go-goldsert"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Assert holds both configuration and implementation of all TOML assertion
+// methods.
+//
+// You can customize serialization by setting the EncoderFunc and DecoderFunc
+// fields to a custom function that returns an encoder/decoder configured as
+// you need.
+//
+// Golden files are handled by the Goldsert field, whose Golden field configures
+// golden file generation, and whose NormalizeLineBreaks field enables
+// line-break normalization of marshaled output and golden files. See the
+// github.com/jimeh/go-goldsert package for details about what can be
+// configured.
+type Assert struct {
+	EncoderFunc func(io.Writer) *toml.Encoder
+	DecoderFunc func(io.Reader) *toml.Decoder
+	Goldsert    *goldsert.Assert
+
+	// DisallowUndecodedKeys causes unmarshaling to fail if the golden file
+	// contains any keys which were not decoded into the target object.
+	DisallowUndecodedKeys bool
+}
+
+// New returns a new *Assert instance configured with default settings.
+//
+// The default encoder indents nested tables with two spaces, and undecoded
+// keys are disallowed.
+func New() *Assert {
+	return &Assert{
+		EncoderFunc:           newEncoder,
+		DecoderFunc:           newDecoder,
+		Goldsert:              goldsert.New(),
+		DisallowUndecodedKeys: true,
+	}
+}
+
+// TOMLMarshaling asserts that the given "v" value TOML marshals to an expected
+// value fetched from a golden file on disk, and then verifies that the
+// marshaled result produces a value that is equal to "v" when unmarshaled.
+//
+// Used for objects that do NOT change when they are marshaled and unmarshaled.
+func (s *Assert) TOMLMarshaling(t *testing.T, v interface{}) {
+	t.Helper()
+
+	s.TOMLMarshalingP(t, v, v)
+}
+
+// TOMLMarshalingP asserts that the given "v" value TOML marshals to an expected
+// value fetched from a golden file on disk, and then verifies that the
+// marshaled result produces a value that is equal to "want" when unmarshaled.
+//
+// Used for objects that change when they are marshaled and unmarshaled.
+func (s *Assert) TOMLMarshalingP(t *testing.T, v, want interface{}) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	err := s.EncoderFunc(&buf).Encode(v)
+	require.NoErrorf(t, err, "failed to TOML marshal %T: %+v", v, v)
+
+	marshaled := s.normalize(buf.Bytes())
+	gold := s.Goldsert.GoldenP(t, "goldsert_toml", marshaled, tomlEqual)
+	tomlEq(t, gold, marshaled)
+
+	if reflect.ValueOf(want).Kind() != reflect.Ptr {
+		require.FailNowf(t,
+			"only pointer types can be asserted",
+			"%T is not a pointer type", want,
+		)
+	}
+
+	got := reflect.New(reflect.TypeOf(want).Elem()).Interface()
+	md, err := s.DecoderFunc(bytes.NewBuffer(gold)).Decode(got)
+	require.NoErrorf(t, err,
+		"failed to TOML unmarshal %T from %s",
+		got, s.Goldsert.Golden.FileP(t, "goldsert_toml"),
+	)
+	if s.DisallowUndecodedKeys {
+		require.Emptyf(t, md.Undecoded(),
+			"golden file %s contains keys not decoded into %T",
+			s.Goldsert.Golden.FileP(t, "goldsert_toml"), got,
+		)
+	}
+	assert.Equal(t, want, got,
+		"unmarshaling from golden file does not match expected object",
+	)
+}
+
+// normalize returns data with line breaks normalized if the NormalizeLineBreaks
+// field of Goldsert is enabled.
+func (s *Assert) normalize(data []byte) []byte {
+	if s.Goldsert.NormalizeLineBreaks {
+		return normalizeLineBreaks(data)
+	}
+
+	return data
+}
+
+// tomlEq asserts that two TOML documents are semantically equal, by decoding
+// both into generic maps and comparing them.
+func tomlEq(t *testing.T, expected, actual []byte) {
+	t.Helper()
+
+	var expectedTOML, actualTOML map[string]interface{}
+	err := toml.Unmarshal(expected, &expectedTOML)
+	require.NoErrorf(t, err,
+		"Expected value ('%s') is not valid toml.\nTOML parsing error: '%s'",
+		expected, err,
+	)
+	err = toml.Unmarshal(actual, &actualTOML)
+	require.NoErrorf(t, err,
+		"Input ('%s') needs to be valid toml.\nTOML parsing error: '%s'",
+		actual, err,
+	)
+
+	assert.Equal(t, expectedTOML, actualTOML)
+}
+
+// tomlEqual returns true if both TOML documents decode into equal generic
+// maps.
+func tomlEqual(gold, data []byte) bool {
+	var a, b map[string]interface{}
+	if toml.Unmarshal(gold, &a) != nil || toml.Unmarshal(data, &b) != nil {
+		return false
+	}
+
+	return assert.ObjectsAreEqual(a, b)
+}
+
+// newEncoder is the default EncoderFunc used by Assert. It returns a
+// *toml.Encoder which is set to indent with two spaces.
+func newEncoder(w io.Writer) *toml.Encoder {
+	enc := toml.NewEncoder(w)
+	enc.Indent = "  "
+
+	return enc
+}
+
+// newDecoder is the default DecoderFunc used by Assert.
+func newDecoder(r io.Reader) *toml.Decoder {
+	return toml.NewDecoder(r)
+}
+
+func normalizeLineBreaks(data []byte) []byte {
+	// Replace CRLF (\r\n, windows) with LF (\n, unix)
+	result := bytes.ReplaceAll(data, []byte{13, 10}, []byte{10})
+	// Replace CR (\r, mac) with LF (\n, unix)
+	result = bytes.ReplaceAll(result, []byte{13}, []byte{10})
+
+	return result
+}
@@ -0,0 +1,25 @@
+package toml
+
+import (
+	"testing"
+)
+
+func TestAssert_TOMLMarshaling(t *testing.T) {
+	for _, tt := range marshalingTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+
+			gs.TOMLMarshaling(t, tt.v)
+		})
+	}
+}
+
+func TestAssert_TOMLMarshalingP(t *testing.T) {
+	for _, tt := range marshalingPTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+
+			gs.TOMLMarshalingP(t, tt.v, tt.want)
+		})
+	}
+}
@@ -0,0 +1,16 @@
+module github.com/jimeh/go-goldsert/toml
+
+go 1.18
+
+require (
+	github.com/BurntSushi/toml v1.5.0
+	github.com/jimeh/go-goldsert v0.2.0
+	github.com/stretchr/testify v1.7.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/jimeh/go-golden v0.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b // indirect
+)
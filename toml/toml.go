@@ -0,0 +1,62 @@
+// Package toml provides goldsert test helpers for TOML, which use golden files
+// to assert marshaling and unmarshaling of given objects.
+//
+// It lives in its own module so that the core goldsert module does not depend
+// on a TOML library.
+//
+// Each test helper operates in two stages:
+//
+// Firstly they marshal the provided object to TOML and reads the corresponding
+// golden file from disk, followed by verifying both are semantically
+// identical.
+//
+// Secondly they unmarshal the content from the golden file, and verifies that
+// the result is identical to the original object. By default unmarshaling
+// fails if the golden file contains any keys which are not decoded into the
+// object.
+//
+// Usage
+//
+//  type Config struct {
+//      Name string `toml:"name"`
+//  }
+//
+//  func TestConfigMarshaling(t *testing.T) {
+//      toml.TOMLMarshaling(t, &Config{Name: "Hello World!"})
+//  }
+//
+// The above example will read from the following golden file:
+//
+//  testdata/TestConfigMarshaling/goldsert_toml.golden
+//
+// To create/update golden files, simply set the GOLDEN_UPDATE environment
+// variable to one of "1", "y", "t", "yes", "on", or "true" when running tests.
+package toml
+
+import (
+	"testing"
+)
+
+var global = New()
+
+// TOMLMarshaling asserts that the given "v" value TOML marshals to an expected
+// value fetched from a golden file on disk, and then verifies that the
+// marshaled result produces a value that is equal to "v" when unmarshaled.
+//
+// Used for objects that do NOT change when they are marshaled and unmarshaled.
+func TOMLMarshaling(t *testing.T, v interface{}) {
+	t.Helper()
+
+	global.TOMLMarshaling(t, v)
+}
+
+// TOMLMarshalingP asserts that the given "v" value TOML marshals to an expected
+// value fetched from a golden file on disk, and then verifies that the
+// marshaled result produces a value that is equal to "want" when unmarshaled.
+//
+// Used for objects that change when they are marshaled and unmarshaled.
+func TOMLMarshalingP(t *testing.T, v, want interface{}) {
+	t.Helper()
+
+	global.TOMLMarshalingP(t, v, want)
+}
@@ -0,0 +1,170 @@
+package toml
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+//
+// Helpers
+//
+
+type Owner struct {
+	Name string `toml:"name"`
+	Team string `toml:"team,omitempty"`
+}
+
+type Database struct {
+	Enabled bool    `toml:"enabled"`
+	Ports   []int   `toml:"ports"`
+	Limits  []Limit `toml:"limits,omitempty"`
+}
+
+type Limit struct {
+	Name  string  `toml:"name"`
+	Value float64 `toml:"value"`
+}
+
+type Config struct {
+	Title    string            `toml:"title"`
+	Owner    *Owner            `toml:"owner,omitempty"`
+	Database *Database         `toml:"database,omitempty"`
+	Servers  map[string]string `toml:"servers,omitempty"`
+	Updated  *time.Time        `toml:"updated,omitempty"`
+
+	Secret string `toml:"-"`
+	order  int
+}
+
+// Version implements encoding.TextMarshaler and encoding.TextUnmarshaler to
+// store itself as a single "major.minor" string.
+type Version struct {
+	Major int
+	Minor int
+}
+
+func (s Version) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d.%d", s.Major, s.Minor)), nil
+}
+
+func (s *Version) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(strings.TrimSpace(string(text)), "%d.%d",
+		&s.Major, &s.Minor,
+	)
+
+	return err
+}
+
+type Release struct {
+	Name    string  `toml:"name"`
+	Version Version `toml:"version"`
+}
+
+var configUpdated = time.Date(
+	2021, time.October, 27, 23, 30, 34, 0, time.UTC,
+)
+
+//
+// Test cases
+//
+
+var marshalingTestCases = []struct {
+	name string
+	v    interface{}
+}{
+	{
+		name: "empty struct",
+		v:    &Config{},
+	},
+	{
+		name: "partial struct",
+		v: &Config{
+			Title: "TOML Example",
+			Owner: &Owner{Name: "Tom Preston-Werner"},
+		},
+	},
+	{
+		name: "full struct",
+		v: &Config{
+			Title: "TOML Example",
+			Owner: &Owner{Name: "Tom Preston-Werner", Team: "Core"},
+			Database: &Database{
+				Enabled: true,
+				Ports:   []int{8000, 8001, 8002},
+				Limits: []Limit{
+					{Name: "connections", Value: 5000},
+					{Name: "load", Value: 0.75},
+				},
+			},
+			Servers: map[string]string{
+				"alpha": "10.0.0.1",
+				"beta":  "10.0.0.2",
+			},
+			Updated: &configUpdated,
+		},
+	},
+	{
+		name: "map",
+		v: &map[string]interface{}{
+			"name":  "hello",
+			"count": int64(42),
+			"tags":  []interface{}{"a", "b"},
+		},
+	},
+	{
+		name: "custom marshaling",
+		v: &Release{
+			Name:    "Hello World!",
+			Version: Version{Major: 4, Minor: 2},
+		},
+	},
+}
+
+var marshalingPTestCases = []struct {
+	name string
+	v    interface{}
+	want interface{}
+}{
+	{
+		name: "empty struct",
+		v:    &Config{},
+		want: &Config{},
+	},
+	{
+		name: "full struct",
+		v: &Config{
+			Title:   "TOML Example",
+			Owner:   &Owner{Name: "Tom Preston-Werner"},
+			Updated: &configUpdated,
+			Secret:  "hunter2",
+			order:   16,
+		},
+		want: &Config{
+			Title:   "TOML Example",
+			Owner:   &Owner{Name: "Tom Preston-Werner"},
+			Updated: &configUpdated,
+		},
+	},
+}
+
+//
+// Tests
+//
+
+func TestTOMLMarshaling(t *testing.T) {
+	for _, tt := range marshalingTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			TOMLMarshaling(t, tt.v)
+		})
+	}
+}
+
+func TestTOMLMarshalingP(t *testing.T) {
+	for _, tt := range marshalingPTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			TOMLMarshalingP(t, tt.v, tt.want)
+		})
+	}
+}
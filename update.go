@@ -0,0 +1,264 @@
+package goldsert
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var falsyStrings = []string{"0", "n", "f", "no", "off", "false"}
+
+// Statuses of golden files written in update mode.
+const (
+	updateCreated   = "created"
+	updateUpdated   = "updated"
+	updateUnchanged = "unchanged"
+)
+
+var updated = newUpdateLog()
+
+// formatPattern matches update filter entries which are format names, like
+// "json" or "msgpack_json", rather than test name patterns.
+var formatPattern = regexp.MustCompile(`^[a-z_]+$`)
+
+// EnvUpdateFilterFunc returns the value of the GOLDSERT_UPDATE environment
+// variable.
+//
+// This is the default UpdateFilterFunc of Assert.
+func EnvUpdateFilterFunc() string {
+	return os.Getenv("GOLDSERT_UPDATE")
+}
+
+// updateFilter selects the golden files to update.
+type updateFilter struct {
+	all     bool
+	formats map[string]bool
+	tests   []*regexp.Regexp
+}
+
+// parseUpdateFilter parses a comma-separated update filter, as described on
+// the UpdateFilterFunc field of Assert. It returns nil if no golden files are
+// to be updated.
+func parseUpdateFilter(s string) (*updateFilter, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || containsString(falsyStrings, s) {
+		return nil, nil
+	}
+	if containsString(truthyStrings, s) {
+		return &updateFilter{all: true}, nil
+	}
+
+	f := &updateFilter{formats: map[string]bool{}}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "":
+			continue
+		case formatPattern.MatchString(part):
+			f.formats[part] = true
+		default:
+			re, err := regexp.Compile(part)
+			if err != nil {
+				return nil, fmt.Errorf("invalid test name pattern: %w", err)
+			}
+			f.tests = append(f.tests, re)
+		}
+	}
+	if len(f.formats) == 0 && len(f.tests) == 0 {
+		return nil, nil
+	}
+
+	return f, nil
+}
+
+// match returns true if the golden file of the given test and format is to be
+// updated.
+func (f *updateFilter) match(test, format string) bool {
+	if f == nil {
+		return false
+	}
+	if f.all {
+		return true
+	}
+	if len(f.formats) > 0 && !f.formats[format] {
+		return false
+	}
+	if len(f.tests) == 0 {
+		return true
+	}
+	for _, re := range f.tests {
+		if re.MatchString(test) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// update returns true if the named golden file of the test is to be updated,
+// either as the Golden field is in update mode, or as it is selected by the
+// filter returned by UpdateFilterFunc. The test fails if golden files are to
+// be updated in a CI environment.
+func (s *Assert) update(t *testing.T, name string) bool {
+	t.Helper()
+
+	update := s.Golden.Update()
+	if !update && s.UpdateFilterFunc != nil {
+		filter := s.UpdateFilterFunc()
+		f, err := parseUpdateFilter(filter)
+		require.NoErrorf(t, err, "invalid golden file update filter %q", filter)
+
+		update = f.match(t.Name(), strings.TrimPrefix(name, "goldsert_"))
+	}
+	if update {
+		s.refuseCIUpdate(t)
+	}
+
+	return update
+}
+
+// write writes data to the named golden file of the test, unless the golden
+// file already equals data as reported by equal, after being normalized with
+// normalize, and records the status of the golden file for the summary printed
+// by Run.
+func (s *Assert) write(
+	t *testing.T,
+	name string,
+	data []byte,
+	equal func(gold, data []byte) bool,
+	normalize func([]byte) []byte,
+) {
+	t.Helper()
+
+	file := s.Golden.FileP(t, name)
+	status, err := s.updateFile(file, data, equal, normalize)
+	require.NoErrorf(t, err, "failed to update golden file %s", file)
+
+	updated.add(file, status)
+}
+
+// updateFile writes data to the given golden file, unless it already equals
+// data as reported by equal, after being normalized with normalize. It returns
+// the status of the golden file, which is one of updateCreated, updateUpdated
+// or updateUnchanged.
+func (s *Assert) updateFile(
+	file string,
+	data []byte,
+	equal func(gold, data []byte) bool,
+	normalize func([]byte) []byte,
+) (string, error) {
+	status := updateCreated
+	gold, err := ioutil.ReadFile(file)
+	switch {
+	case err == nil && equal(normalize(gold), data):
+		return updateUnchanged, nil
+	case err == nil:
+		status = updateUpdated
+	case !os.IsNotExist(err):
+		return "", err
+	}
+
+	err = os.MkdirAll(filepath.Dir(file), s.Golden.DirMode)
+	if err == nil {
+		err = ioutil.WriteFile(file, data, s.Golden.FileMode)
+	}
+
+	return status, err
+}
+
+// updateLog records the status of golden files written in update mode.
+type updateLog struct {
+	mu    sync.Mutex
+	files map[string]string
+}
+
+func newUpdateLog() *updateLog {
+	return &updateLog{files: map[string]string{}}
+}
+
+// add records the status of the given golden file. A golden file which was
+// created or updated stays so when it is written again unchanged.
+func (l *updateLog) add(file, status string) {
+	if abs, err := filepath.Abs(file); err == nil {
+		file = abs
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if prev := l.files[file]; prev == "" || prev == updateUnchanged {
+		l.files[file] = status
+	}
+}
+
+// report writes a summary of the golden files written in update mode, listing
+// created and updated golden files, and also unchanged golden files when
+// verbose is true. Golden files which no longer exist, like those within
+// temporary directories, are left out. Nothing is written if no golden files
+// remain.
+func (l *updateLog) report(w io.Writer, verbose bool) {
+	l.mu.Lock()
+	files := make(map[string]string, len(l.files))
+	for path, status := range l.files {
+		files[path] = status
+	}
+	l.mu.Unlock()
+
+	paths := make([]string, 0, len(files))
+	counts := map[string]int{}
+	for path, status := range files {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+		paths = append(paths, path)
+		counts[status]++
+	}
+	if len(paths) == 0 {
+		return
+	}
+	sort.Strings(paths)
+
+	fmt.Fprintf(w,
+		"goldsert: golden files: %d created, %d updated, %d unchanged\n",
+		counts[updateCreated], counts[updateUpdated], counts[updateUnchanged],
+	)
+
+	wd, _ := os.Getwd()
+	for _, path := range paths {
+		status := files[path]
+		if status != updateUnchanged || verbose {
+			fmt.Fprintf(w, "goldsert: %s golden file: %s\n",
+				status, relPath(wd, path),
+			)
+		}
+	}
+}
+
+// relPath returns path relative to dir, or path as is if it cannot be made
+// relative.
+func relPath(dir, path string) string {
+	if rel, err := filepath.Rel(dir, path); err == nil {
+		return rel
+	}
+
+	return path
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
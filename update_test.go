@@ -0,0 +1,251 @@
+package goldsert
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseUpdateFilter(t *testing.T) {
+	tests := []struct {
+		filter string
+		// want maps "<test> <format>" to the expected match result.
+		want    map[string]bool
+		wantErr string
+	}{
+		{
+			filter: "",
+			want: map[string]bool{
+				"TestBook/full json": false,
+			},
+		},
+		{
+			filter: "false",
+			want: map[string]bool{
+				"TestBook/full json": false,
+			},
+		},
+		{
+			filter: "1",
+			want: map[string]bool{
+				"TestBook/full json":  true,
+				"TestOrder/empty xml": true,
+			},
+		},
+		{
+			filter: "json",
+			want: map[string]bool{
+				"TestBook/full json":   true,
+				"TestOrder/empty json": true,
+				"TestBook/full yaml":   false,
+				"TestBook/full jsonl":  false,
+			},
+		},
+		{
+			filter: " yaml, xml ",
+			want: map[string]bool{
+				"TestBook/full json": false,
+				"TestBook/full yaml": true,
+				"TestBook/full xml":  true,
+			},
+		},
+		{
+			filter: "toml,msgpack_json",
+			want: map[string]bool{
+				"TestBook/full toml":         true,
+				"TestBook/full msgpack_json": true,
+				"TestBook/full msgpack":      false,
+				"TestBook/full json":         false,
+			},
+		},
+		{
+			filter: "TestBook.*/full",
+			want: map[string]bool{
+				"TestBook/full json":       true,
+				"TestBookshelf/full yaml":  true,
+				"TestBook/empty json":      false,
+				"TestOrder/full json":      false,
+				"TestBook/full/nested xml": true,
+			},
+		},
+		{
+			filter: "json,^TestBook/,^TestOrder/full$",
+			want: map[string]bool{
+				"TestBook/full json":   true,
+				"TestBook/full yaml":   false,
+				"TestOrder/full json":  true,
+				"TestOrder/empty json": false,
+				"TestShelf/full json":  false,
+			},
+		},
+		{
+			filter:  "json,TestBook(",
+			wantErr: "invalid test name pattern: error parsing regexp",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			f, err := parseUpdateFilter(tt.filter)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+
+			for k, want := range tt.want {
+				i := strings.LastIndex(k, " ")
+				test, format := k[:i], k[i+1:]
+
+				assert.Equalf(t, want, f.match(test, format),
+					"match(%q, %q)", test, format,
+				)
+			}
+		})
+	}
+}
+
+func TestAssert_update(t *testing.T) {
+	gs := New()
+	gs.CIFunc = func() bool { return false }
+	gs.Golden.UpdateFunc = func() bool { return false }
+
+	gs.UpdateFilterFunc = func() string { return "yaml,TestAssert_update/b" }
+	t.Run("a", func(t *testing.T) {
+		assert.False(t, gs.update(t, "goldsert_yaml"))
+	})
+	t.Run("b", func(t *testing.T) {
+		assert.True(t, gs.update(t, "goldsert_yaml"))
+		assert.False(t, gs.update(t, "goldsert_json"))
+	})
+
+	gs.UpdateFilterFunc = nil
+	assert.False(t, gs.update(t, "goldsert_yaml"))
+
+	gs.Golden.UpdateFunc = func() bool { return true }
+	assert.True(t, gs.update(t, "goldsert_yaml"))
+}
+
+func TestEnvUpdateFilterFunc(t *testing.T) {
+	defer os.Setenv("GOLDSERT_UPDATE", os.Getenv("GOLDSERT_UPDATE"))
+	os.Setenv("GOLDSERT_UPDATE", "json,TestBook")
+
+	assert.Equal(t, "json,TestBook", EnvUpdateFilterFunc())
+}
+
+func TestAssert_updateFile(t *testing.T) {
+	tests := []struct {
+		name       string
+		gold       string
+		data       string
+		want       string
+		wantStatus string
+	}{
+		{
+			name:       "missing golden file",
+			data:       "{\"id\":1}\n",
+			want:       "{\"id\":1}\n",
+			wantStatus: updateCreated,
+		},
+		{
+			name:       "equal golden file",
+			gold:       "{\n  \"id\": 1\r\n}\n",
+			data:       "{\"id\":1}\n",
+			want:       "{\n  \"id\": 1\r\n}\n",
+			wantStatus: updateUnchanged,
+		},
+		{
+			name:       "changed golden file",
+			gold:       "{\"id\":1}\n",
+			data:       "{\"id\":2}\n",
+			want:       "{\"id\":2}\n",
+			wantStatus: updateUpdated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "goldsert-")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+
+			file := filepath.Join(dir, "TestBook", "goldsert_json.golden")
+			if tt.gold != "" {
+				require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+				err := ioutil.WriteFile(file, []byte(tt.gold), 0o644)
+				require.NoError(t, err)
+			}
+
+			gs := New()
+			status, err := gs.updateFile(
+				file, []byte(tt.data), jsonEqual, gs.normalize,
+			)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantStatus, status)
+			got, err := ioutil.ReadFile(file)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func Test_updateLog_report(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goldsert-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	prev, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	defer func() { _ = os.Chdir(prev) }()
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	writePruneTestFiles(t, wd, []string{
+		"testdata/TestA/goldsert_json.golden",
+		"testdata/TestB/goldsert_json.golden",
+		"testdata/TestC/goldsert_yaml.golden",
+		"testdata/TestD/goldsert_xml.golden",
+	})
+
+	l := newUpdateLog()
+	var buf bytes.Buffer
+	l.add("testdata/TestRemoved/goldsert_json.golden", updateCreated)
+	l.report(&buf, false)
+	assert.Empty(t, buf.String())
+
+	l.add("testdata/TestB/goldsert_json.golden", updateUnchanged)
+	l.add("testdata/TestA/goldsert_json.golden", updateCreated)
+	l.add("testdata/TestA/goldsert_json.golden", updateUnchanged)
+	l.add("testdata/TestC/goldsert_yaml.golden", updateUnchanged)
+	l.add("testdata/TestC/goldsert_yaml.golden", updateUpdated)
+	l.add(filepath.Join(wd, "testdata/TestD/goldsert_xml.golden"),
+		updateUnchanged,
+	)
+
+	l.report(&buf, false)
+	assert.Equal(t,
+		"goldsert: golden files: 1 created, 1 updated, 2 unchanged\n"+
+			"goldsert: created golden file: "+
+			filepath.FromSlash("testdata/TestA/goldsert_json.golden")+"\n"+
+			"goldsert: updated golden file: "+
+			filepath.FromSlash("testdata/TestC/goldsert_yaml.golden")+"\n",
+		buf.String(),
+	)
+
+	buf.Reset()
+	l.report(&buf, true)
+	assert.Contains(t, buf.String(),
+		"goldsert: unchanged golden file: "+
+			filepath.FromSlash("testdata/TestB/goldsert_json.golden")+"\n",
+	)
+	assert.Contains(t, buf.String(),
+		"goldsert: unchanged golden file: "+
+			filepath.FromSlash("testdata/TestD/goldsert_xml.golden")+"\n",
+	)
+}
@@ -0,0 +1,275 @@
+package goldsert
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const xmlNamespaceURI = "http://www.w3.org/XML/1998/namespace"
+
+var (
+	errUndeclaredXMLPrefix = errors.New("undeclared XML namespace prefix")
+	errMalformedXML        = errors.New("malformed XML")
+)
+
+// canonicalizeXML returns the Exclusive XML Canonicalization 1.0 (without
+// comments) form of the given XML document, as defined by
+// https://www.w3.org/TR/xml-exc-c14n/.
+//
+// The XML declaration, DTD and comments are removed, empty elements are
+// expanded to start/end tag pairs, attributes are sorted, text and attribute
+// values are re-escaped in canonical form, and namespace declarations are only
+// rendered on the elements which visibly utilize them.
+func canonicalizeXML(data []byte) ([]byte, error) {
+	c := &xmlCanonicalizer{}
+	dec := xml.NewDecoder(bytes.NewReader(data))
+
+	for {
+		tok, err := dec.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			err = c.startElement(tok)
+		case xml.EndElement:
+			err = c.endElement(tok)
+		case xml.CharData:
+			if len(c.scopes) > 0 {
+				c.buf.WriteString(escapeC14NText(string(tok)))
+			}
+		case xml.ProcInst:
+			c.procInst(tok)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(c.names) > 0 {
+		return nil, fmt.Errorf(
+			"%w: unclosed element <%s>",
+			errMalformedXML, qualifiedXMLName(c.names[len(c.names)-1]),
+		)
+	}
+
+	return c.buf.Bytes(), nil
+}
+
+type xmlCanonicalizer struct {
+	buf bytes.Buffer
+
+	// names holds the names of each open element.
+	names []xml.Name
+
+	// scopes holds the namespace declarations of each open element in the
+	// input document.
+	scopes []map[string]string
+
+	// rendered holds the namespace declarations rendered on each open element
+	// in the output document.
+	rendered []map[string]string
+
+	// seenRoot is true once the document element has been closed.
+	seenRoot bool
+}
+
+func (c *xmlCanonicalizer) startElement(start xml.StartElement) error {
+	decls := map[string]string{}
+	attrs := make([]xml.Attr, 0, len(start.Attr))
+	for _, attr := range start.Attr {
+		switch {
+		case attr.Name.Space == "xmlns":
+			decls[attr.Name.Local] = attr.Value
+		case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+			decls[""] = attr.Value
+		default:
+			attrs = append(attrs, attr)
+		}
+	}
+	c.names = append(c.names, start.Name)
+	c.scopes = append(c.scopes, decls)
+
+	used := []string{start.Name.Space}
+	for _, attr := range attrs {
+		if attr.Name.Space != "" {
+			used = append(used, attr.Name.Space)
+		}
+	}
+
+	render := map[string]string{}
+	for _, prefix := range used {
+		if prefix == "xml" {
+			continue
+		}
+
+		uri, declared := lookupXMLNamespace(c.scopes, prefix)
+		if !declared && prefix != "" {
+			return fmt.Errorf("%w: %s", errUndeclaredXMLPrefix, prefix)
+		}
+
+		current, ok := lookupXMLNamespace(c.rendered, prefix)
+		if current != uri || (!ok && prefix != "") {
+			render[prefix] = uri
+		}
+	}
+	c.rendered = append(c.rendered, render)
+
+	prefixes := make([]string, 0, len(render))
+	for prefix := range render {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+
+	sort.SliceStable(attrs, func(i, j int) bool {
+		ui, uj := c.attrNamespace(attrs[i]), c.attrNamespace(attrs[j])
+		if ui != uj {
+			return ui < uj
+		}
+
+		return attrs[i].Name.Local < attrs[j].Name.Local
+	})
+
+	c.buf.WriteString("<" + qualifiedXMLName(start.Name))
+	for _, prefix := range prefixes {
+		name := "xmlns"
+		if prefix != "" {
+			name += ":" + prefix
+		}
+		c.writeAttr(name, render[prefix])
+	}
+	for _, attr := range attrs {
+		c.writeAttr(qualifiedXMLName(attr.Name), attr.Value)
+	}
+	c.buf.WriteString(">")
+
+	return nil
+}
+
+// attrNamespace returns the namespace URI of an attribute. Unlike elements,
+// unprefixed attributes are in no namespace, rather than the default one.
+func (c *xmlCanonicalizer) attrNamespace(attr xml.Attr) string {
+	if attr.Name.Space == "" {
+		return ""
+	}
+	uri, _ := lookupXMLNamespace(c.scopes, attr.Name.Space)
+
+	return uri
+}
+
+func (c *xmlCanonicalizer) endElement(end xml.EndElement) error {
+	if len(c.names) == 0 || c.names[len(c.names)-1] != end.Name {
+		return fmt.Errorf(
+			"%w: unexpected end element </%s>",
+			errMalformedXML, qualifiedXMLName(end.Name),
+		)
+	}
+
+	c.buf.WriteString("</" + qualifiedXMLName(end.Name) + ">")
+
+	c.names = c.names[:len(c.names)-1]
+	c.scopes = c.scopes[:len(c.scopes)-1]
+	c.rendered = c.rendered[:len(c.rendered)-1]
+	if len(c.scopes) == 0 {
+		c.seenRoot = true
+	}
+
+	return nil
+}
+
+func (c *xmlCanonicalizer) procInst(pi xml.ProcInst) {
+	if pi.Target == "xml" {
+		return
+	}
+
+	if len(c.scopes) == 0 && c.seenRoot {
+		c.buf.WriteString("\n")
+	}
+
+	c.buf.WriteString("<?" + pi.Target)
+	if len(pi.Inst) > 0 {
+		c.buf.WriteString(" " + string(pi.Inst))
+	}
+	c.buf.WriteString("?>")
+
+	if len(c.scopes) == 0 && !c.seenRoot {
+		c.buf.WriteString("\n")
+	}
+}
+
+func (c *xmlCanonicalizer) writeAttr(name, value string) {
+	c.buf.WriteString(" " + name + `="` + escapeC14NAttr(value) + `"`)
+}
+
+// lookupXMLNamespace returns the namespace URI bound to the given prefix in
+// the innermost scope that declares it. The "xml" prefix is always bound.
+func lookupXMLNamespace(
+	scopes []map[string]string,
+	prefix string,
+) (string, bool) {
+	if prefix == "xml" {
+		return xmlNamespaceURI, true
+	}
+
+	for i := len(scopes) - 1; i >= 0; i-- {
+		if uri, ok := scopes[i][prefix]; ok {
+			return uri, true
+		}
+	}
+
+	return "", false
+}
+
+func qualifiedXMLName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+
+	return name.Space + ":" + name.Local
+}
+
+var (
+	c14nTextReplacer = strings.NewReplacer(
+		"&", "&amp;",
+		"<", "&lt;",
+		">", "&gt;",
+		"\r", "&#xD;",
+	)
+	c14nAttrReplacer = strings.NewReplacer(
+		"&", "&amp;",
+		"<", "&lt;",
+		`"`, "&quot;",
+		"\t", "&#x9;",
+		"\n", "&#xA;",
+		"\r", "&#xD;",
+	)
+)
+
+func escapeC14NText(s string) string {
+	return c14nTextReplacer.Replace(s)
+}
+
+func escapeC14NAttr(s string) string {
+	return c14nAttrReplacer.Replace(s)
+}
+
+// xmlEqual reports if the content of an XML golden file equals marshaled XML,
+// canonicalizing the golden file first if CanonicalXML is enabled.
+func (s *Assert) xmlEqual(gold, data []byte) bool {
+	if s.CanonicalXML {
+		var err error
+		if gold, err = canonicalizeXML(gold); err != nil {
+			return false
+		}
+	}
+
+	return bytes.Equal(gold, data)
+}
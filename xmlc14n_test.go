@@ -0,0 +1,177 @@
+package goldsert
+
+import (
+	"encoding/xml"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Assertion struct {
+	XMLName   xml.Name   `xml:"urn:oasis:names:tc:SAML:2.0:assertion Assertion"`
+	ID        string     `xml:"ID,attr"`
+	Version   string     `xml:"Version,attr"`
+	Issuer    string     `xml:"urn:oasis:names:tc:SAML:2.0:assertion Issuer"`
+	Signature *Signature `xml:"http://www.w3.org/2000/09/xmldsig# Signature,omitempty"`
+}
+
+type Signature struct {
+	SignatureValue string `xml:"http://www.w3.org/2000/09/xmldsig# SignatureValue"`
+}
+
+func newCompactXMLEncoder(w io.Writer) *xml.Encoder {
+	return xml.NewEncoder(w)
+}
+
+func TestAssert_XMLMarshalingP_CanonicalXML(t *testing.T) {
+	tests := append(marshalingPTestCases, []struct {
+		name string
+		v    interface{}
+		want interface{}
+	}{
+		{
+			name: "namespaced struct",
+			v: &Assertion{
+				ID:      "_d71a3a8e9fcc45c9e9d248ef7049393fc8f04e5f75",
+				Version: "2.0",
+				Issuer:  "https://idp.example.org/SAML2",
+				Signature: &Signature{
+					SignatureValue: "bm90IGEgcmVhbCBzaWduYXR1cmU=",
+				},
+			},
+			want: &Assertion{
+				XMLName: xml.Name{
+					Space: "urn:oasis:names:tc:SAML:2.0:assertion",
+					Local: "Assertion",
+				},
+				ID:      "_d71a3a8e9fcc45c9e9d248ef7049393fc8f04e5f75",
+				Version: "2.0",
+				Issuer:  "https://idp.example.org/SAML2",
+				Signature: &Signature{
+					SignatureValue: "bm90IGEgcmVhbCBzaWduYXR1cmU=",
+				},
+			},
+		},
+	}...)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+			gs.XMLEncoderFunc = newCompactXMLEncoder
+			gs.CanonicalXML = true
+
+			gs.XMLMarshalingP(t, tt.v, tt.want)
+		})
+	}
+}
+
+func Test_canonicalizeXML(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "declaration, comments and whitespace outside root",
+			data: "<?xml version=\"1.0\"?>\n\n<!DOCTYPE doc>\n" +
+				"<!-- comment -->\n<doc>Hello<!-- comment --></doc>\n" +
+				"<!-- comment -->\n",
+			want: "<doc>Hello</doc>",
+		},
+		{
+			name: "processing instructions outside root",
+			data: "<?xml-stylesheet href=\"doc.xsl\"?>\n<doc/>\n<?pi-end?>",
+			want: "<?xml-stylesheet href=\"doc.xsl\"?>\n" +
+				"<doc></doc>\n<?pi-end?>",
+		},
+		{
+			name: "empty elements",
+			data: `<doc><e1   /><e2 ></e2>` +
+				`<e3 name = "elem3" id="elem3" /></doc>`,
+			want: `<doc><e1></e1><e2></e2>` +
+				`<e3 id="elem3" name="elem3"></e3></doc>`,
+		},
+		{
+			name: "text escaping",
+			data: "<doc>&lt;&amp;&gt;&quot;&apos;&#xD;\r\n" +
+				"<![CDATA[<b>]]></doc>",
+			want: "<doc>&lt;&amp;&gt;\"'&#xD;\n&lt;b&gt;</doc>",
+		},
+		{
+			name: "attribute escaping",
+			data: `<doc a="&lt;&amp;&gt;&quot;'&#x9;&#xA;&#xD;"></doc>`,
+			want: `<doc a="&lt;&amp;>&quot;'&#x9;&#xA;&#xD;"></doc>`,
+		},
+		{
+			name: "attribute ordering",
+			data: `<doc xmlns:b="urn:b" xmlns:a="urn:z" ` +
+				`b:x="1" a:x="2" z="3" xml:lang="en" c="4"></doc>`,
+			want: `<doc xmlns:a="urn:z" xmlns:b="urn:b" c="4" z="3" ` +
+				`xml:lang="en" b:x="1" a:x="2"></doc>`,
+		},
+		{
+			name: "attribute ordering with default namespace",
+			data: `<doc xmlns="urn:z" xmlns:a="urn:a" a:x="1" y="2"></doc>`,
+			want: `<doc xmlns="urn:z" xmlns:a="urn:a" y="2" a:x="1"></doc>`,
+		},
+		{
+			name: "unused namespace declarations",
+			data: `<n0:root xmlns:n0="urn:n0" xmlns:n1="urn:n1" ` +
+				`xmlns="urn:default"><n0:child/></n0:root>`,
+			want: `<n0:root xmlns:n0="urn:n0"><n0:child></n0:child></n0:root>`,
+		},
+		{
+			name: "namespace declarations rendered where utilized",
+			data: `<root xmlns="urn:default" xmlns:n1="urn:n1">` +
+				`<n1:a><b n1:attr="1"/></n1:a></root>`,
+			want: `<root xmlns="urn:default"><n1:a xmlns:n1="urn:n1">` +
+				`<b n1:attr="1"></b></n1:a></root>`,
+		},
+		{
+			name: "redeclared namespaces",
+			data: `<a:root xmlns:a="urn:a"><a:child xmlns:a="urn:a">` +
+				`<a:leaf xmlns:a="urn:other"/></a:child></a:root>`,
+			want: `<a:root xmlns:a="urn:a"><a:child>` +
+				`<a:leaf xmlns:a="urn:other"></a:leaf></a:child></a:root>`,
+		},
+		{
+			name: "undeclared default namespace",
+			data: `<root xmlns="urn:default"><child xmlns=""/></root>`,
+			want: `<root xmlns="urn:default"><child xmlns=""></child></root>`,
+		},
+		{
+			name:    "undeclared prefix",
+			data:    `<a:root></a:root>`,
+			wantErr: "undeclared XML namespace prefix: a",
+		},
+		{
+			name:    "unclosed element",
+			data:    `<root><child></child>`,
+			wantErr: "malformed XML: unclosed element <root>",
+		},
+		{
+			name:    "mismatched end element",
+			data:    `<root><a:child xmlns:a="urn:a"></b:child></root>`,
+			wantErr: "malformed XML: unexpected end element </b:child>",
+		},
+		{
+			name: "syntax error",
+			data: `<root><</root>`,
+			wantErr: "XML syntax error on line 1: " +
+				"expected element name after <",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := canonicalizeXML([]byte(tt.data))
+
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, string(got))
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package goldsert
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// marshalXML marshals v to XML with the XMLEncoderFunc, applying the
+// XMLHeader, XMLRootName, XMLTrailingNewline and XMLNamespace options.
+func (s *Assert) marshalXML(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if s.XMLHeader {
+		buf.WriteString(xml.Header)
+	}
+
+	var err error
+	enc := s.XMLEncoderFunc(&buf)
+	if start := s.xmlStartElement(v); start != nil {
+		err = enc.EncodeElement(v, *start)
+	} else {
+		err = enc.Encode(v)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if s.XMLTrailingNewline {
+		buf.WriteString("\n")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// newXMLDecoder returns a decoder from XMLDecoderFunc, with its default
+// namespace set to XMLNamespace unless already set.
+func (s *Assert) newXMLDecoder(r io.Reader) *xml.Decoder {
+	dec := s.XMLDecoderFunc(r)
+	if s.XMLNamespace != "" && dec.DefaultSpace == "" {
+		dec.DefaultSpace = s.XMLNamespace
+	}
+
+	return dec
+}
+
+// xmlStartElement returns the root element to marshal v with, or nil if the
+// default root element chosen by encoding/xml should be used.
+func (s *Assert) xmlStartElement(v interface{}) *xml.StartElement {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() == reflect.Invalid || rv.Kind() == reflect.Ptr {
+		return nil
+	}
+
+	var name xml.Name
+	if rv.Kind() == reflect.Struct {
+		name = xmlStructName(rv)
+	} else if s.XMLRootName != "" {
+		name.Local = s.XMLRootName
+	}
+
+	if name.Local == "" && s.XMLNamespace == "" {
+		return nil
+	}
+
+	if name.Local == "" {
+		name.Local = rv.Type().Name()
+	}
+	// Unnamed types, like []int or anonymous structs, have no name to use for
+	// the root element, so encoding/xml is left to choose or reject it.
+	if name.Local == "" {
+		return nil
+	}
+	if name.Space == "" {
+		name.Space = s.XMLNamespace
+	}
+
+	return &xml.StartElement{Name: name}
+}
+
+// xmlStructName returns the element name encoding/xml would use for the given
+// struct value, based on its XMLName field value or tag.
+func xmlStructName(rv reflect.Value) xml.Name {
+	f, ok := rv.Type().FieldByName("XMLName")
+	if !ok || f.Type != xmlNameType {
+		return xml.Name{}
+	}
+
+	name, _ := rv.FieldByIndex(f.Index).Interface().(xml.Name)
+	if name.Local != "" {
+		return name
+	}
+
+	tag := f.Tag.Get("xml")
+	if i := strings.Index(tag, ","); i >= 0 {
+		tag = tag[:i]
+	}
+
+	return parseXMLName(tag)
+}
@@ -0,0 +1,85 @@
+package goldsert
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssert_XMLMarshalingP_XMLHeader(t *testing.T) {
+	for _, tt := range marshalingPTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+			gs.XMLHeader = true
+			gs.XMLTrailingNewline = true
+
+			gs.XMLMarshalingP(t, tt.v, tt.want)
+		})
+	}
+}
+
+func TestAssert_XMLMarshalingP_XMLNamespace(t *testing.T) {
+	for _, tt := range marshalingPTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+			gs.XMLRootName = "value"
+			gs.XMLNamespace = "urn:goldsert:test"
+
+			gs.XMLMarshalingP(t, tt.v, tt.want)
+		})
+	}
+}
+
+func TestAssert_XMLMarshalingP_XMLNamespaceStrict(t *testing.T) {
+	for _, tt := range marshalingPTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+			gs.XMLHeader = true
+			gs.XMLRootName = "value"
+			gs.XMLTrailingNewline = true
+			gs.XMLNamespace = "urn:goldsert:test"
+			gs.StrictXMLDecoding = true
+
+			gs.XMLMarshalingP(t, tt.v, tt.want)
+		})
+	}
+}
+
+func TestAssert_xmlStartElement(t *testing.T) {
+	tests := []struct {
+		name     string
+		rootName string
+		v        interface{}
+		want     *xml.StartElement
+	}{
+		{
+			name: "named type",
+			v:    &Author{},
+			want: &xml.StartElement{
+				Name: xml.Name{Space: "urn:goldsert:test", Local: "Author"},
+			},
+		},
+		{
+			name:     "slice with root name",
+			rootName: "values",
+			v:        []int{1, 2},
+			want: &xml.StartElement{
+				Name: xml.Name{Space: "urn:goldsert:test", Local: "values"},
+			},
+		},
+		{name: "slice", v: []int{1, 2}, want: nil},
+		{name: "anonymous struct", v: &struct{ ID int }{ID: 1}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+			gs.XMLRootName = tt.rootName
+			gs.XMLNamespace = "urn:goldsert:test"
+
+			got := gs.xmlStartElement(tt.v)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
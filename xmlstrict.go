@@ -0,0 +1,312 @@
+package goldsert
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+var (
+	xmlNameType         = reflect.TypeOf(xml.Name{})
+	xmlUnmarshalerType  = reflect.TypeOf((*xml.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf(
+		(*encoding.TextUnmarshaler)(nil),
+	).Elem()
+	errUnknownXMLContent = errors.New("unknown XML content")
+)
+
+// xmlStructInfo describes which attributes and child elements a struct type
+// consumes when unmarshaled with encoding/xml.
+type xmlStructInfo struct {
+	attrs    []xml.Name
+	anyAttr  bool
+	innerXML bool
+	elements []*xmlElementInfo
+	anyElem  reflect.Type
+}
+
+// xmlElementInfo describes a child element consumed by a struct field. Elements
+// which are intermediate parents of a "a>b" field path have no type, but
+// instead have info about their own children.
+type xmlElementInfo struct {
+	name     xml.Name
+	typ      reflect.Type
+	children *xmlStructInfo
+}
+
+// checkXMLKnownFields walks the given XML document and returns an error listing
+// all elements and attributes which would not be consumed when unmarshaling the
+// document into a value of type typ with encoding/xml.
+//
+// Types which implement xml.Unmarshaler are assumed to consume all of their
+// content, as there is no way to know what they do with it.
+func checkXMLKnownFields(data []byte, typ reflect.Type) error {
+	c := &xmlChecker{dec: xml.NewDecoder(bytes.NewReader(data))}
+
+	for {
+		tok, err := c.dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		if start, ok := tok.(xml.StartElement); ok {
+			err = c.element(start, typ, start.Name.Local)
+			if err != nil {
+				return err
+			}
+
+			break
+		}
+	}
+
+	if len(c.unknown) > 0 {
+		return fmt.Errorf(
+			"%w: %s", errUnknownXMLContent, strings.Join(c.unknown, ", "),
+		)
+	}
+
+	return nil
+}
+
+type xmlChecker struct {
+	dec     *xml.Decoder
+	unknown []string
+}
+
+func (c *xmlChecker) element(
+	start xml.StartElement,
+	typ reflect.Type,
+	path string,
+) error {
+	typ = indirectType(typ)
+	if typ.Kind() == reflect.Slice && typ.Elem().Kind() != reflect.Uint8 {
+		typ = indirectType(typ.Elem())
+	}
+
+	if reflect.PtrTo(typ).Implements(xmlUnmarshalerType) {
+		return c.dec.Skip()
+	}
+
+	if typ.Kind() == reflect.Struct &&
+		!reflect.PtrTo(typ).Implements(textUnmarshalerType) {
+		return c.structElement(start, xmlStructInfoOf(typ), path)
+	}
+
+	return c.structElement(start, &xmlStructInfo{}, path)
+}
+
+func (c *xmlChecker) structElement(
+	start xml.StartElement,
+	info *xmlStructInfo,
+	path string,
+) error {
+	if info.innerXML {
+		return c.dec.Skip()
+	}
+
+	if !info.anyAttr {
+		for _, attr := range start.Attr {
+			if isXMLNamespaceAttr(attr.Name) ||
+				matchXMLName(info.attrs, attr.Name) {
+				continue
+			}
+			c.unknown = append(c.unknown, path+"/@"+attr.Name.Local)
+		}
+	}
+
+	for {
+		tok, err := c.dec.Token()
+		if err != nil {
+			return err
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			childPath := path + "/" + tok.Name.Local
+
+			switch child := info.element(tok.Name); {
+			case child != nil && child.children != nil:
+				err = c.structElement(tok, child.children, childPath)
+			case child != nil:
+				err = c.element(tok, child.typ, childPath)
+			case info.anyElem != nil:
+				err = c.element(tok, info.anyElem, childPath)
+			default:
+				c.unknown = append(c.unknown, childPath)
+				err = c.dec.Skip()
+			}
+			if err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+func (s *xmlStructInfo) element(name xml.Name) *xmlElementInfo {
+	for _, el := range s.elements {
+		if el.name.Local == name.Local &&
+			(el.name.Space == "" || el.name.Space == name.Space) {
+			return el
+		}
+	}
+
+	return nil
+}
+
+// xmlStructInfoOf returns info about the attributes and elements consumed by
+// the given struct type, following the same field rules as encoding/xml.
+func xmlStructInfoOf(typ reflect.Type) *xmlStructInfo {
+	info := &xmlStructInfo{}
+	info.addFields(typ)
+
+	return info
+}
+
+func (s *xmlStructInfo) addFields(typ reflect.Type) {
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if (f.PkgPath != "" && !f.Anonymous) || f.Name == "XMLName" {
+			continue
+		}
+
+		tag := f.Tag.Get("xml")
+		if tag == "-" {
+			continue
+		}
+
+		name, flags := tag, ""
+		if i := strings.Index(tag, ","); i >= 0 {
+			name, flags = tag[:i], tag[i+1:]
+		}
+
+		ft := indirectType(f.Type)
+		if f.Anonymous && name == "" && flags == "" {
+			if ft.Kind() == reflect.Struct {
+				s.addFields(ft)
+			}
+
+			continue
+		}
+		if f.PkgPath != "" || ft == xmlNameType {
+			continue
+		}
+
+		if name == "" {
+			name = xmlTypeName(ft)
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		s.addField(name, strings.Split(flags, ","), f.Type)
+	}
+}
+
+func (s *xmlStructInfo) addField(
+	name string,
+	flags []string,
+	typ reflect.Type,
+) {
+	var attr, anyElem bool
+	for _, flag := range flags {
+		switch flag {
+		case "attr":
+			attr = true
+		case "any":
+			anyElem = true
+		case "innerxml":
+			s.innerXML = true
+
+			return
+		case "chardata", "cdata", "comment":
+			return
+		}
+	}
+
+	switch {
+	case attr && anyElem:
+		s.anyAttr = true
+	case attr:
+		s.attrs = append(s.attrs, parseXMLName(name))
+	case anyElem:
+		s.anyElem = typ
+	default:
+		s.addElement(strings.Split(name, ">"), typ)
+	}
+}
+
+func (s *xmlStructInfo) addElement(path []string, typ reflect.Type) {
+	name := parseXMLName(path[0])
+	if len(path) == 1 {
+		s.elements = append(s.elements, &xmlElementInfo{name: name, typ: typ})
+
+		return
+	}
+
+	parent := s.element(name)
+	if parent == nil || parent.children == nil {
+		parent = &xmlElementInfo{name: name, children: &xmlStructInfo{}}
+		s.elements = append(s.elements, parent)
+	}
+	parent.children.addElement(path[1:], typ)
+}
+
+// xmlTypeName returns the element name declared by the XMLName field tag of the
+// given struct type, if any.
+func xmlTypeName(typ reflect.Type) string {
+	if typ.Kind() != reflect.Struct {
+		return ""
+	}
+
+	f, ok := typ.FieldByName("XMLName")
+	if !ok || f.Type != xmlNameType {
+		return ""
+	}
+
+	name := f.Tag.Get("xml")
+	if i := strings.Index(name, ","); i >= 0 {
+		name = name[:i]
+	}
+
+	return name
+}
+
+// parseXMLName parses a "namespace-URL name" style xml struct tag name.
+func parseXMLName(s string) xml.Name {
+	if i := strings.LastIndex(s, " "); i >= 0 {
+		return xml.Name{Space: s[:i], Local: s[i+1:]}
+	}
+
+	return xml.Name{Local: s}
+}
+
+func matchXMLName(names []xml.Name, name xml.Name) bool {
+	for _, n := range names {
+		if n.Local == name.Local && (n.Space == "" || n.Space == name.Space) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isXMLNamespaceAttr(name xml.Name) bool {
+	return name.Space == "xmlns" || (name.Space == "" && name.Local == "xmlns")
+}
+
+func indirectType(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	return typ
+}
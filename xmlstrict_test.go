@@ -0,0 +1,156 @@
+package goldsert
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Library struct {
+	XMLName xml.Name `xml:"library"`
+	Name    string   `xml:"name,attr"`
+	Books   []*Book  `xml:"books>book"`
+	Comics  []Comic  `xml:"comic"`
+	Shelf   *Shelf
+	Note    string  `xml:",comment"`
+	Extras  []Extra `xml:",any"`
+}
+
+type Shelf struct {
+	XMLName xml.Name   `xml:"shelf"`
+	Label   string     `xml:",chardata"`
+	Attrs   []xml.Attr `xml:",any,attr"`
+}
+
+type Extra struct {
+	Raw string `xml:",innerxml"`
+}
+
+func TestAssert_XMLMarshalingP_StrictXMLDecoding(t *testing.T) {
+	for _, tt := range marshalingPTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+			gs.StrictXMLDecoding = true
+
+			gs.XMLMarshalingP(t, tt.v, tt.want)
+		})
+	}
+}
+
+func Test_checkXMLKnownFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		v       interface{}
+		wantErr string
+	}{
+		{
+			name: "scalar",
+			data: `<int>42</int>`,
+			v:    intPtr(0),
+		},
+		{
+			name:    "scalar with attribute",
+			data:    `<int base="10">42</int>`,
+			v:       intPtr(0),
+			wantErr: "unknown XML content: int/@base",
+		},
+		{
+			name:    "scalar with element",
+			data:    `<string>hello <b>world</b></string>`,
+			v:       stringPtr(""),
+			wantErr: "unknown XML content: string/b",
+		},
+		{
+			name: "struct",
+			data: `<Book>
+  <id>1</id>
+  <title>The Traveler</title>
+  <author><first_name>John</first_name></author>
+  <year>2005</year>
+</Book>`,
+			v: &Book{},
+		},
+		{
+			name: "struct with unknown elements and attributes",
+			data: `<Book lang="en">
+  <id>1</id>
+  <isbn>978-0307265715</isbn>
+  <author id="42">
+    <first_name>John</first_name>
+    <middle_name>T</middle_name>
+  </author>
+</Book>`,
+			v: &Book{},
+			wantErr: "unknown XML content: Book/@lang, Book/isbn, " +
+				"Book/author/@id, Book/author/middle_name",
+		},
+		{
+			name: "namespace declarations",
+			data: `<Book xmlns="urn:books" xmlns:x="urn:x"><id>1</id></Book>`,
+			v:    &Book{},
+		},
+		{
+			name: "xml.Unmarshaler",
+			data: `<Comic id="1" issue="2" extra="3">Hi<b>!</b></Comic>`,
+			v:    &Comic{},
+		},
+		{
+			name: "time.Time",
+			data: `<Article><id>1</id>` +
+				`<date>2021-10-27T22:30:34Z</date></Article>`,
+			v: &Article{},
+		},
+		{
+			name:    "time.Time with child element",
+			data:    `<Article><date><year>2021</year></date></Article>`,
+			v:       &Article{},
+			wantErr: "unknown XML content: Article/date/year",
+		},
+		{
+			name:    "ignored field",
+			data:    `<Article><id>1</id><Rank>8</Rank></Article>`,
+			v:       &Article{},
+			wantErr: "unknown XML content: Article/Rank",
+		},
+		{
+			name: "nested paths, any attrs, any elements and comments",
+			data: `<library name="Main">
+  <!-- hello -->
+  <books>
+    <book><id>1</id></book>
+    <book><id>2</id></book>
+  </books>
+  <comic id="3">Hello</comic>
+  <shelf a="1" b="2">Top</shelf>
+  <magazine><issue>4</issue></magazine>
+</library>`,
+			v: &Library{},
+		},
+		{
+			name: "unknown nested paths",
+			data: `<library>
+  <books size="2">
+    <book><id>1</id><isbn>123</isbn></book>
+    <dvd>Hello</dvd>
+  </books>
+</library>`,
+			v: &Library{},
+			wantErr: "unknown XML content: library/books/@size, " +
+				"library/books/book/isbn, library/books/dvd",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkXMLKnownFields([]byte(tt.data), reflect.TypeOf(tt.v))
+
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package goldsert
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// YAMLDocumentsMarshaling asserts that the given "docs" values YAML marshal to
+// an expected multi-document YAML stream fetched from a golden file on disk,
+// and then verifies that unmarshaling each document of the stream produces a
+// value that is equal to the corresponding value in "docs".
+//
+// Each document is unmarshaled into a new value of the same type as its
+// corresponding value in "docs", allowing a stream to hold documents of
+// different types.
+//
+// Used for objects that do NOT change when they are marshaled and unmarshaled.
+func (s *Assert) YAMLDocumentsMarshaling(t *testing.T, docs ...interface{}) {
+	t.Helper()
+
+	s.YAMLDocumentsMarshalingP(t, docs, docs)
+}
+
+// YAMLDocumentsMarshalingP asserts that the given "docs" values YAML marshal to
+// an expected multi-document YAML stream fetched from a golden file on disk,
+// and then verifies that unmarshaling each document of the stream produces a
+// value that is equal to the corresponding value in "want".
+//
+// Each document is unmarshaled into a new value of the same type as its
+// corresponding value in "want", allowing a stream to hold documents of
+// different types.
+//
+// Used for objects that change when they are marshaled and unmarshaled.
+func (s *Assert) YAMLDocumentsMarshalingP(
+	t *testing.T,
+	docs []interface{},
+	want []interface{},
+) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	enc := s.YAMLEncoderFunc(&buf)
+	for i, doc := range docs {
+		err := enc.Encode(doc)
+		require.NoErrorf(t, err,
+			"failed to YAML marshal document %d %T: %+v", i+1, doc, doc,
+		)
+	}
+
+	marshaled := s.normalize(buf.Bytes())
+	gold := s.GoldenP(t,
+		"goldsert_yaml_docs", marshaled, yamlDocumentsEqual,
+	)
+
+	goldDocs, err := decodeYAMLDocuments(gold)
+	require.NoErrorf(t, err,
+		"failed to YAML unmarshal documents from %s",
+		s.Golden.FileP(t, "goldsert_yaml_docs"),
+	)
+	marshaledDocs, err := decodeYAMLDocuments(marshaled)
+	require.NoError(t, err, "failed to YAML unmarshal marshaled documents")
+
+	assert.Lenf(t, marshaledDocs, len(goldDocs),
+		"YAML stream does not have the same number of documents as %s",
+		s.Golden.FileP(t, "goldsert_yaml_docs"),
+	)
+	for i := 0; i < len(marshaledDocs) && i < len(goldDocs); i++ {
+		assert.Equalf(t, goldDocs[i], marshaledDocs[i],
+			"document %d of YAML stream does not match golden file", i+1,
+		)
+	}
+
+	dec := s.YAMLDecoderFunc(bytes.NewBuffer(gold))
+	for i, w := range want {
+		requirePtr(t, w)
+
+		got := reflect.New(reflect.TypeOf(w).Elem()).Interface()
+		err = dec.Decode(got)
+		require.NoErrorf(t, err,
+			"failed to YAML unmarshal %T from document %d of %s",
+			got, i+1, s.Golden.FileP(t, "goldsert_yaml_docs"),
+		)
+		assert.Equalf(t, w, got,
+			"unmarshaling document %d from golden file does not match "+
+				"expected object", i+1,
+		)
+	}
+
+	var extra yaml.Node
+	err = dec.Decode(&extra)
+	assert.Truef(t, errors.Is(err, io.EOF),
+		"golden file %s contains more than the %d expected documents",
+		s.Golden.FileP(t, "goldsert_yaml_docs"), len(want),
+	)
+}
+
+// decodeYAMLDocuments decodes every document in the given YAML stream into
+// generic values.
+func decodeYAMLDocuments(data []byte) ([]interface{}, error) {
+	var docs []interface{}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc interface{}
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return docs, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		docs = append(docs, doc)
+	}
+}
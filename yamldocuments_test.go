@@ -0,0 +1,129 @@
+package goldsert
+
+import (
+	"testing"
+)
+
+var yamlDocumentsTestCases = []struct {
+	name string
+	docs []interface{}
+}{
+	{
+		name: "no documents",
+		docs: []interface{}{},
+	},
+	{
+		name: "single document",
+		docs: []interface{}{
+			&Book{
+				ID:    "cfda163c-d5c1-44a2-909b-5d2ce3a31979",
+				Title: "The Traveler",
+			},
+		},
+	},
+	{
+		name: "same type documents",
+		docs: []interface{}{
+			&Book{
+				ID:    "cfda163c-d5c1-44a2-909b-5d2ce3a31979",
+				Title: "The Traveler",
+				Author: &Author{
+					FirstName: "John",
+					LastName:  "Twelve Hawks",
+				},
+				Year: 2005,
+			},
+			&Book{
+				ID:    "f9b8d5b4-42b8-44c3-b8e1-3c3e4eb8fc2f",
+				Title: "The Dark River",
+				Year:  2007,
+			},
+		},
+	},
+	{
+		name: "mixed type documents",
+		docs: []interface{}{
+			&Author{FirstName: "John", LastName: "Twelve Hawks"},
+			&Book{
+				ID:    "cfda163c-d5c1-44a2-909b-5d2ce3a31979",
+				Title: "The Traveler",
+			},
+			stringPtr("hello world"),
+			&Comic{
+				ID:    "2fd5af35-b85e-4f03-8eba-524be28d7a5b",
+				Name:  "Hello World!",
+				Issue: "Forty Two",
+			},
+		},
+	},
+}
+
+var yamlDocumentsPTestCases = []struct {
+	name string
+	docs []interface{}
+	want []interface{}
+}{
+	{
+		name: "no documents",
+		docs: []interface{}{},
+		want: []interface{}{},
+	},
+	{
+		name: "mixed type documents",
+		docs: []interface{}{
+			&Article{
+				ID:    "10eec54d-e30a-4428-be18-01095d889126",
+				Title: "Time Travel",
+				Author: &Author{
+					FirstName: "Doc",
+					LastName:  "Brown",
+				},
+				Date:  &articleDate,
+				Rank:  8,
+				order: 16,
+			},
+			&Comic{
+				ID:      "2fd5af35-b85e-4f03-8eba-524be28d7a5b",
+				Name:    "Hello World!",
+				Issue:   "Forty Two",
+				Ignored: "don't pay attention to this :)",
+			},
+		},
+		want: []interface{}{
+			&Article{
+				ID:    "10eec54d-e30a-4428-be18-01095d889126",
+				Title: "Time Travel",
+				Author: &Author{
+					FirstName: "Doc",
+					LastName:  "Brown",
+				},
+				Date: &articleDate,
+			},
+			&Comic{
+				ID:    "2fd5af35-b85e-4f03-8eba-524be28d7a5b",
+				Name:  "Hello World!",
+				Issue: "Forty Two",
+			},
+		},
+	},
+}
+
+func TestAssert_YAMLDocumentsMarshaling(t *testing.T) {
+	for _, tt := range yamlDocumentsTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+
+			gs.YAMLDocumentsMarshaling(t, tt.docs...)
+		})
+	}
+}
+
+func TestAssert_YAMLDocumentsMarshalingP(t *testing.T) {
+	for _, tt := range yamlDocumentsPTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := New()
+
+			gs.YAMLDocumentsMarshalingP(t, tt.docs, tt.want)
+		})
+	}
+}